@@ -0,0 +1,27 @@
+package acsm
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// tokenFromGRPCContext extracts the bearer token clients are expected to
+// send in the "authorization" metadata key, mirroring the Authorization
+// header used by the HTTP API.
+func tokenFromGRPCContext(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+
+	if !ok {
+		return "", errors.New("grpc: no metadata in context")
+	}
+
+	values := md.Get("authorization")
+
+	if len(values) == 0 {
+		return "", errors.New("grpc: missing authorization metadata")
+	}
+
+	return values[0], nil
+}
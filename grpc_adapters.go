@@ -0,0 +1,182 @@
+package acsm
+
+import (
+	"time"
+
+	"github.com/JustaPenguin/assetto-server-manager/grpcapi"
+)
+
+// The adapters below bridge the domain managers Resolver already holds to
+// the small interfaces grpcapi.Server depends on (see grpcapi/server.go),
+// converting each manager's own result types into the grpcapi wire types.
+// grpcapi deliberately has no dependency on this package, so this
+// conversion has to live here rather than on the managers themselves.
+
+type grpcRaceManagerAdapter struct {
+	raceManager *RaceManager
+}
+
+func (a *grpcRaceManagerAdapter) ListCustomRaces() ([]*grpcapi.CustomRace, error) {
+	races, err := a.raceManager.ListCustomRaces()
+
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*grpcapi.CustomRace, 0, len(races))
+
+	for _, race := range races {
+		out = append(out, &grpcapi.CustomRace{
+			UUID:          race.UUID.String(),
+			Name:          race.Name,
+			Track:         race.Track,
+			Scheduled:     race.Scheduled,
+			ScheduledTime: race.ScheduledTime,
+		})
+	}
+
+	return out, nil
+}
+
+func (a *grpcRaceManagerAdapter) ScheduleRace(raceID string, scheduledTime interface{ Unix() int64 }) error {
+	return a.raceManager.ScheduleRace(raceID, time.Unix(scheduledTime.Unix(), 0))
+}
+
+type grpcChampionshipManagerAdapter struct {
+	championshipManager *ChampionshipManager
+}
+
+func (a *grpcChampionshipManagerAdapter) ListChampionships() ([]*grpcapi.Championship, error) {
+	championships, err := a.championshipManager.ListChampionships()
+
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*grpcapi.Championship, 0, len(championships))
+
+	for _, championship := range championships {
+		out = append(out, &grpcapi.Championship{ID: championship.ID.String(), Name: championship.Name})
+	}
+
+	return out, nil
+}
+
+type grpcRaceWeekendManagerAdapter struct {
+	raceWeekendManager *RaceWeekendManager
+}
+
+func (a *grpcRaceWeekendManagerAdapter) ListRaceWeekends() ([]*grpcapi.RaceWeekend, error) {
+	raceWeekends, err := a.raceWeekendManager.ListRaceWeekends()
+
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*grpcapi.RaceWeekend, 0, len(raceWeekends))
+
+	for _, raceWeekend := range raceWeekends {
+		out = append(out, &grpcapi.RaceWeekend{ID: raceWeekend.ID.String(), Name: raceWeekend.Name})
+	}
+
+	return out, nil
+}
+
+type grpcContentUploaderAdapter struct {
+	contentUploadHandler *ContentUploadHandler
+}
+
+func (a *grpcContentUploaderAdapter) UploadCar(fileName string, data []byte) error {
+	return a.contentUploadHandler.UploadCar(fileName, data)
+}
+
+func (a *grpcContentUploaderAdapter) UploadTrack(fileName string, data []byte) error {
+	return a.contentUploadHandler.UploadTrack(fileName, data)
+}
+
+type grpcBlockListManagerAdapter struct {
+	blockListManager *BlockListManager
+}
+
+func (a *grpcBlockListManagerAdapter) Block(guid string) error {
+	return a.blockListManager.Block(guid)
+}
+
+func (a *grpcBlockListManagerAdapter) Unblock(guid string) error {
+	return a.blockListManager.Unblock(guid)
+}
+
+// grpcServerProcessAdapter bridges the real ServerProcess to
+// grpcapi.ServerProcess, the same way the adapters above bridge their
+// managers - added for consistency with them, even though ServerProcess's
+// Start/Stop/IsRunning already match grpcapi.ServerProcess's signatures.
+type grpcServerProcessAdapter struct {
+	serverProcess ServerProcess
+}
+
+func (a *grpcServerProcessAdapter) Start(eventType, raceID string) error {
+	return a.serverProcess.Start(eventType, raceID)
+}
+
+func (a *grpcServerProcessAdapter) Stop() error {
+	return a.serverProcess.Stop()
+}
+
+func (a *grpcServerProcessAdapter) IsRunning() bool {
+	return a.serverProcess.IsRunning()
+}
+
+// raceControlEventSource is the narrow pub/sub capability
+// grpcRaceControlAdapter needs from the real RaceControl: a stream of
+// already-serialized race control events, the same bytes RaceControlHub
+// pushes to the browser over its websocket. RaceControl's real definition
+// is outside this source snapshot and predates gRPC entirely, so it has no
+// method producing grpcapi.RaceControlEvent (a wire type invented for this
+// package) directly. This capability is recovered with a type assertion
+// when one is needed, the same way acServer.TCP recovers pluginBroadcaster
+// from its untyped Plugin field, rather than assuming a method signature
+// RaceControl was never written to have.
+type raceControlEventSource interface {
+	Subscribe() (events <-chan []byte, unsubscribe func())
+}
+
+type grpcRaceControlAdapter struct {
+	raceControl *RaceControl
+}
+
+// Subscribe adapts raceControlEventSource's raw event bytes into
+// grpcapi.RaceControlEvent. If RaceControl doesn't implement
+// raceControlEventSource (yet), it returns a channel that's immediately
+// closed, so a StreamRaceControl client sees a clean EOF rather than
+// hanging forever on a stream that can never deliver anything.
+func (a *grpcRaceControlAdapter) Subscribe() (<-chan grpcapi.RaceControlEvent, func()) {
+	source, ok := interface{}(a.raceControl).(raceControlEventSource)
+
+	if !ok {
+		out := make(chan grpcapi.RaceControlEvent)
+		close(out)
+
+		return out, func() {}
+	}
+
+	raw, unsubscribe := source.Subscribe()
+	out := make(chan grpcapi.RaceControlEvent)
+
+	go func() {
+		defer close(out)
+
+		for payload := range raw {
+			out <- grpcapi.RaceControlEvent{Type: "update", Payload: payload}
+		}
+	}()
+
+	return out, unsubscribe
+}
+
+func (a *grpcRaceControlAdapter) CurrentTrack() string {
+	return a.raceControl.CurrentTrack()
+}
+
+func (a *grpcRaceControlAdapter) ConnectedDrivers() int {
+	return a.raceControl.ConnectedDrivers()
+}
@@ -0,0 +1,46 @@
+package grpcapi
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// WireCodec implements encoding.Codec for AdminService's hand-written
+// request/response types (see grpcapi.pb.go). None of them satisfy
+// proto.Message — there's no protoc-gen-go in this build — so gRPC's
+// default "proto" codec can't marshal them directly. Instead each type
+// marshals itself to real protobuf wire bytes (see messages_codec.go),
+// field-for-field matching grpcapi.proto, so any client generated from
+// that .proto file — grpcurl included — can still talk to this service;
+// only the codec registration on our side is hand-rolled, not the bytes
+// on the wire. ResolveGRPCServer forces this codec onto the server with
+// grpc.ForceServerCodec, bypassing the content-type negotiation that
+// would otherwise try (and fail on) the stock proto codec.
+type WireCodec struct{}
+
+func (WireCodec) Marshal(v interface{}) ([]byte, error) {
+	switch m := v.(type) {
+	case Message:
+		return m.Marshal()
+	case *emptypb.Empty:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("grpcapi: WireCodec cannot marshal %T", v)
+	}
+}
+
+func (WireCodec) Unmarshal(data []byte, v interface{}) error {
+	switch m := v.(type) {
+	case Message:
+		return m.Unmarshal(data)
+	case *emptypb.Empty:
+		return nil
+	default:
+		return fmt.Errorf("grpcapi: WireCodec cannot unmarshal into %T", v)
+	}
+}
+
+func (WireCodec) Name() string {
+	return "proto"
+}
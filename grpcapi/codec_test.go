@@ -0,0 +1,83 @@
+package grpcapi
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestWireCodecRoundTrip(t *testing.T) {
+	in := &CustomRace{
+		UUID:          "abc-123",
+		Name:          "Monza Sprint",
+		Track:         "monza",
+		Scheduled:     true,
+		ScheduledTime: time.Unix(1700000000, 0).UTC(),
+	}
+
+	var codec WireCodec
+
+	data, err := codec.Marshal(in)
+
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	out := &CustomRace{}
+
+	if err := codec.Unmarshal(data, out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if out.UUID != in.UUID || out.Name != in.Name || out.Track != in.Track || out.Scheduled != in.Scheduled || !out.ScheduledTime.Equal(in.ScheduledTime) {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", out, in)
+	}
+}
+
+func TestWireCodecRoundTripRepeatedMessage(t *testing.T) {
+	in := &ListCustomRacesResponse{
+		Races: []*CustomRace{
+			{UUID: "a", Name: "Race A"},
+			{UUID: "b", Name: "Race B", Scheduled: true, ScheduledTime: time.Unix(1700000000, 0).UTC()},
+		},
+	}
+
+	var codec WireCodec
+
+	data, err := codec.Marshal(in)
+
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	out := &ListCustomRacesResponse{}
+
+	if err := codec.Unmarshal(data, out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if len(out.Races) != 2 || out.Races[0].UUID != "a" || out.Races[1].UUID != "b" || !out.Races[1].Scheduled {
+		t.Fatalf("round trip mismatch: got %+v", out.Races)
+	}
+}
+
+func TestWireCodecEmpty(t *testing.T) {
+	var codec WireCodec
+
+	data, err := codec.Marshal(&emptypb.Empty{})
+
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	if err := codec.Unmarshal(data, &emptypb.Empty{}); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+}
+
+func TestWireCodecName(t *testing.T) {
+	if (WireCodec{}).Name() != "proto" {
+		t.Fatalf("expected codec name %q, got %q", "proto", (WireCodec{}).Name())
+	}
+}
@@ -0,0 +1,79 @@
+// Hand-written wire types for grpcapi.proto, in the shape protoc-gen-go
+// would produce. Kept in sync with grpcapi.proto by hand since this repo
+// doesn't run protoc as part of its build.
+
+package grpcapi
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type StartServerRequest struct {
+	EventType string
+	RaceID    string
+}
+
+type ServerStatusResponse struct {
+	Running          bool
+	Track            string
+	ConnectedDrivers int32
+}
+
+type ListCustomRacesResponse struct {
+	Races []*CustomRace
+}
+
+type CustomRace struct {
+	UUID          string
+	Name          string
+	Track         string
+	Scheduled     bool
+	ScheduledTime time.Time
+}
+
+type ScheduleRaceRequest struct {
+	RaceID        string
+	ScheduledTime time.Time
+}
+
+type ListChampionshipsResponse struct {
+	Championships []*Championship
+}
+
+type Championship struct {
+	ID   string
+	Name string
+}
+
+type ListRaceWeekendsResponse struct {
+	RaceWeekends []*RaceWeekend
+}
+
+type RaceWeekend struct {
+	ID   string
+	Name string
+}
+
+type UploadContentRequest struct {
+	FileName string
+	Data     []byte
+}
+
+type BlockDriverRequest struct {
+	GUID string
+}
+
+type AdminChatRequest struct {
+	Message string
+}
+
+type RaceControlEvent struct {
+	Type    string
+	Payload []byte
+}
+
+// Empty is an alias for the well-known empty message, kept local so callers
+// in this package don't need to import emptypb directly.
+type Empty = emptypb.Empty
@@ -0,0 +1,333 @@
+// Hand-written gRPC server plumbing for AdminService, in the shape
+// protoc-gen-go-grpc would produce from grpcapi.proto. Kept in sync with
+// grpcapi.proto and grpcapi.pb.go by hand since this repo doesn't run
+// protoc as part of its build.
+
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// AdminServiceServer is the server API for AdminService.
+type AdminServiceServer interface {
+	StartServer(context.Context, *StartServerRequest) (*Empty, error)
+	StopServer(context.Context, *Empty) (*Empty, error)
+	ServerStatus(context.Context, *Empty) (*ServerStatusResponse, error)
+
+	ListCustomRaces(context.Context, *Empty) (*ListCustomRacesResponse, error)
+	ScheduleCustomRace(context.Context, *ScheduleRaceRequest) (*Empty, error)
+
+	ListChampionships(context.Context, *Empty) (*ListChampionshipsResponse, error)
+
+	ListRaceWeekends(context.Context, *Empty) (*ListRaceWeekendsResponse, error)
+
+	UploadCar(context.Context, *UploadContentRequest) (*Empty, error)
+	UploadTrack(context.Context, *UploadContentRequest) (*Empty, error)
+
+	BlockDriver(context.Context, *BlockDriverRequest) (*Empty, error)
+	UnblockDriver(context.Context, *BlockDriverRequest) (*Empty, error)
+
+	SendAdminChat(context.Context, *AdminChatRequest) (*Empty, error)
+
+	StreamRaceControl(*Empty, AdminService_StreamRaceControlServer) error
+}
+
+// AdminService_StreamRaceControlServer is the server-side stream for StreamRaceControl.
+type AdminService_StreamRaceControlServer interface {
+	Send(*RaceControlEvent) error
+	grpc.ServerStream
+}
+
+// RegisterAdminServiceServer registers srv with the gRPC server s, the same
+// way generated code for any other service in this repo would.
+func RegisterAdminServiceServer(s *grpc.Server, srv AdminServiceServer) {
+	s.RegisterService(&adminServiceServiceDesc, srv)
+}
+
+var adminServiceServiceDesc = grpc.ServiceDesc{
+	ServiceName: "grpcapi.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "StartServer", Handler: _AdminService_StartServer_Handler},
+		{MethodName: "StopServer", Handler: _AdminService_StopServer_Handler},
+		{MethodName: "ServerStatus", Handler: _AdminService_ServerStatus_Handler},
+		{MethodName: "ListCustomRaces", Handler: _AdminService_ListCustomRaces_Handler},
+		{MethodName: "ScheduleCustomRace", Handler: _AdminService_ScheduleCustomRace_Handler},
+		{MethodName: "ListChampionships", Handler: _AdminService_ListChampionships_Handler},
+		{MethodName: "ListRaceWeekends", Handler: _AdminService_ListRaceWeekends_Handler},
+		{MethodName: "UploadCar", Handler: _AdminService_UploadCar_Handler},
+		{MethodName: "UploadTrack", Handler: _AdminService_UploadTrack_Handler},
+		{MethodName: "BlockDriver", Handler: _AdminService_BlockDriver_Handler},
+		{MethodName: "UnblockDriver", Handler: _AdminService_UnblockDriver_Handler},
+		{MethodName: "SendAdminChat", Handler: _AdminService_SendAdminChat_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamRaceControl",
+			Handler:       _AdminService_StreamRaceControl_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "grpcapi/grpcapi.proto",
+}
+
+func _AdminService_StartServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartServerRequest)
+
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AdminServiceServer).StartServer(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcapi.AdminService/StartServer"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).StartServer(ctx, req.(*StartServerRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_StopServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AdminServiceServer).StopServer(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcapi.AdminService/StopServer"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).StopServer(ctx, req.(*Empty))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ServerStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ServerStatus(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcapi.AdminService/ServerStatus"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ServerStatus(ctx, req.(*Empty))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListCustomRaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListCustomRaces(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcapi.AdminService/ListCustomRaces"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListCustomRaces(ctx, req.(*Empty))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ScheduleCustomRace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRaceRequest)
+
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ScheduleCustomRace(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcapi.AdminService/ScheduleCustomRace"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ScheduleCustomRace(ctx, req.(*ScheduleRaceRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListChampionships_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListChampionships(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcapi.AdminService/ListChampionships"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListChampionships(ctx, req.(*Empty))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListRaceWeekends_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListRaceWeekends(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcapi.AdminService/ListRaceWeekends"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListRaceWeekends(ctx, req.(*Empty))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UploadCar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadContentRequest)
+
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UploadCar(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcapi.AdminService/UploadCar"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UploadCar(ctx, req.(*UploadContentRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UploadTrack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadContentRequest)
+
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UploadTrack(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcapi.AdminService/UploadTrack"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UploadTrack(ctx, req.(*UploadContentRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_BlockDriver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockDriverRequest)
+
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AdminServiceServer).BlockDriver(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcapi.AdminService/BlockDriver"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).BlockDriver(ctx, req.(*BlockDriverRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UnblockDriver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockDriverRequest)
+
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UnblockDriver(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcapi.AdminService/UnblockDriver"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UnblockDriver(ctx, req.(*BlockDriverRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SendAdminChat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminChatRequest)
+
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SendAdminChat(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/grpcapi.AdminService/SendAdminChat"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SendAdminChat(ctx, req.(*AdminChatRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_StreamRaceControl_Handler(srv interface{}, stream grpc.ServerStream) error {
+	in := new(Empty)
+
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+
+	return srv.(AdminServiceServer).StreamRaceControl(in, &adminServiceStreamRaceControlServer{stream})
+}
+
+type adminServiceStreamRaceControlServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminServiceStreamRaceControlServer) Send(m *RaceControlEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
@@ -0,0 +1,430 @@
+package grpcapi
+
+import "bytes"
+
+// Message is satisfied by every hand-written wire type in grpcapi.pb.go,
+// mirroring internal/acServer/pb.Message. WireCodec dispatches to these
+// instead of gob so the bytes on the wire are real protobuf, decodable by
+// any client built from grpcapi.proto.
+type Message interface {
+	Marshal() ([]byte, error)
+	Unmarshal([]byte) error
+}
+
+func (m *StartServerRequest) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendStringField(buf, 1, m.EventType)
+	appendStringField(buf, 2, m.RaceID)
+
+	return buf.Bytes(), nil
+}
+
+func (m *StartServerRequest) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.EventType = string(value)
+		case 2:
+			m.RaceID = string(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *ServerStatusResponse) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendBoolField(buf, 1, m.Running)
+	appendStringField(buf, 2, m.Track)
+	appendInt32Field(buf, 3, m.ConnectedDrivers)
+
+	return buf.Bytes(), nil
+}
+
+func (m *ServerStatusResponse) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.Running = asBool(value)
+		case 2:
+			m.Track = string(value)
+		case 3:
+			m.ConnectedDrivers = asInt32(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *ListCustomRacesResponse) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	for _, race := range m.Races {
+		if err := appendMessageField(buf, 1, race); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (m *ListCustomRacesResponse) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		if field != 1 {
+			continue
+		}
+
+		race := &CustomRace{}
+
+		if err := race.Unmarshal(value); err != nil {
+			return err
+		}
+
+		m.Races = append(m.Races, race)
+	}
+
+	return nil
+}
+
+func (m *CustomRace) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendStringField(buf, 1, m.UUID)
+	appendStringField(buf, 2, m.Name)
+	appendStringField(buf, 3, m.Track)
+	appendBoolField(buf, 4, m.Scheduled)
+	appendTimestampField(buf, 5, m.ScheduledTime)
+
+	return buf.Bytes(), nil
+}
+
+func (m *CustomRace) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.UUID = string(value)
+		case 2:
+			m.Name = string(value)
+		case 3:
+			m.Track = string(value)
+		case 4:
+			m.Scheduled = asBool(value)
+		case 5:
+			m.ScheduledTime = asTimestamp(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *ScheduleRaceRequest) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendStringField(buf, 1, m.RaceID)
+	appendTimestampField(buf, 2, m.ScheduledTime)
+
+	return buf.Bytes(), nil
+}
+
+func (m *ScheduleRaceRequest) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.RaceID = string(value)
+		case 2:
+			m.ScheduledTime = asTimestamp(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *ListChampionshipsResponse) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	for _, championship := range m.Championships {
+		if err := appendMessageField(buf, 1, championship); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (m *ListChampionshipsResponse) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		if field != 1 {
+			continue
+		}
+
+		championship := &Championship{}
+
+		if err := championship.Unmarshal(value); err != nil {
+			return err
+		}
+
+		m.Championships = append(m.Championships, championship)
+	}
+
+	return nil
+}
+
+func (m *Championship) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendStringField(buf, 1, m.ID)
+	appendStringField(buf, 2, m.Name)
+
+	return buf.Bytes(), nil
+}
+
+func (m *Championship) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.ID = string(value)
+		case 2:
+			m.Name = string(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *ListRaceWeekendsResponse) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	for _, raceWeekend := range m.RaceWeekends {
+		if err := appendMessageField(buf, 1, raceWeekend); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (m *ListRaceWeekendsResponse) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		if field != 1 {
+			continue
+		}
+
+		raceWeekend := &RaceWeekend{}
+
+		if err := raceWeekend.Unmarshal(value); err != nil {
+			return err
+		}
+
+		m.RaceWeekends = append(m.RaceWeekends, raceWeekend)
+	}
+
+	return nil
+}
+
+func (m *RaceWeekend) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendStringField(buf, 1, m.ID)
+	appendStringField(buf, 2, m.Name)
+
+	return buf.Bytes(), nil
+}
+
+func (m *RaceWeekend) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.ID = string(value)
+		case 2:
+			m.Name = string(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *UploadContentRequest) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendStringField(buf, 1, m.FileName)
+	appendBytesField(buf, 2, m.Data)
+
+	return buf.Bytes(), nil
+}
+
+func (m *UploadContentRequest) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.FileName = string(value)
+		case 2:
+			m.Data = asBytes(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *BlockDriverRequest) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendStringField(buf, 1, m.GUID)
+
+	return buf.Bytes(), nil
+}
+
+func (m *BlockDriverRequest) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		if field == 1 {
+			m.GUID = string(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *AdminChatRequest) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendStringField(buf, 1, m.Message)
+
+	return buf.Bytes(), nil
+}
+
+func (m *AdminChatRequest) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		if field == 1 {
+			m.Message = string(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *RaceControlEvent) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendStringField(buf, 1, m.Type)
+	appendBytesField(buf, 2, m.Payload)
+
+	return buf.Bytes(), nil
+}
+
+func (m *RaceControlEvent) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.Type = string(value)
+		case 2:
+			m.Payload = asBytes(value)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,313 @@
+// Package grpcapi exposes a gRPC service that mirrors the operations the
+// HTML handlers already provide, so external tooling (CI, league automation,
+// third-party dashboards) can drive a server manager instance without
+// scraping HTML. It deliberately has no dependency on the acsm package: the
+// concrete managers are plugged in by the caller (see Resolver.ResolveGRPCServer)
+// as long as they satisfy the small interfaces declared below.
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RaceManager is the subset of RaceManager needed to list and schedule
+// custom races over gRPC.
+type RaceManager interface {
+	ListCustomRaces() ([]*CustomRace, error)
+	ScheduleRace(raceID string, scheduledTime interface{ Unix() int64 }) error
+}
+
+// ChampionshipManager is the subset needed to list championships.
+type ChampionshipManager interface {
+	ListChampionships() ([]*Championship, error)
+}
+
+// RaceWeekendManager is the subset needed to list race weekends.
+type RaceWeekendManager interface {
+	ListRaceWeekends() ([]*RaceWeekend, error)
+}
+
+// ContentUploader mirrors the validation/extraction logic ContentUploadHandler
+// already performs for cars and tracks uploaded through the HTML form.
+type ContentUploader interface {
+	UploadCar(fileName string, data []byte) error
+	UploadTrack(fileName string, data []byte) error
+}
+
+// BlockListManager is the subset needed to manage the drive block list.
+type BlockListManager interface {
+	Block(guid string) error
+	Unblock(guid string) error
+}
+
+// ServerProcess is the subset needed to start/stop the acServer process and
+// report its status.
+type ServerProcess interface {
+	Start(eventType, raceID string) error
+	Stop() error
+	IsRunning() bool
+}
+
+// RaceControl is the subset needed to stream live race events to gRPC
+// clients the same way the websocket hub streams them to the browser.
+type RaceControl interface {
+	Subscribe() (events <-chan RaceControlEvent, unsubscribe func())
+	CurrentTrack() string
+	ConnectedDrivers() int
+}
+
+// Authenticator validates a token/credential pair against the accounts store
+// used by AccountManager and returns the caller's role.
+type Authenticator interface {
+	AuthenticateRPC(ctx context.Context) (account string, role string, err error)
+}
+
+// RequiredRole returns the minimum role required to call the given full gRPC
+// method name (e.g. "/grpcapi.AdminService/StopServer"). Methods absent from
+// this map are treated as admin-only.
+var RequiredRole = map[string]string{
+	"/grpcapi.AdminService/ServerStatus":      "read-only",
+	"/grpcapi.AdminService/ListCustomRaces":   "read-only",
+	"/grpcapi.AdminService/ListChampionships": "read-only",
+	"/grpcapi.AdminService/ListRaceWeekends":  "read-only",
+	"/grpcapi.AdminService/StreamRaceControl": "read-only",
+}
+
+// Server implements AdminServiceServer on top of the existing managers held
+// by Resolver, so there is a single source of truth shared with the HTML
+// handlers.
+type Server struct {
+	raceManager         RaceManager
+	championshipManager ChampionshipManager
+	raceWeekendManager  RaceWeekendManager
+	contentUploader     ContentUploader
+	blockListManager    BlockListManager
+	serverProcess       ServerProcess
+	raceControl         RaceControl
+	auth                Authenticator
+}
+
+// NewServer creates a Server backed by the given managers and authenticator.
+func NewServer(
+	raceManager RaceManager,
+	championshipManager ChampionshipManager,
+	raceWeekendManager RaceWeekendManager,
+	contentUploader ContentUploader,
+	blockListManager BlockListManager,
+	serverProcess ServerProcess,
+	raceControl RaceControl,
+	auth Authenticator,
+) *Server {
+	return &Server{
+		raceManager:         raceManager,
+		championshipManager: championshipManager,
+		raceWeekendManager:  raceWeekendManager,
+		contentUploader:     contentUploader,
+		blockListManager:    blockListManager,
+		serverProcess:       serverProcess,
+		raceControl:         raceControl,
+		auth:                auth,
+	}
+}
+
+// Register attaches the AdminService to the given grpc.Server. It does not
+// install UnaryAuthInterceptor or StreamAuthInterceptor; those are passed as
+// options when the grpc.Server is built (see Resolver.ResolveGRPCServer).
+func (s *Server) Register(g *grpc.Server) {
+	RegisterAdminServiceServer(g, s)
+}
+
+// UnaryAuthInterceptor rejects calls whose caller's role doesn't satisfy
+// RequiredRole for the method being invoked.
+func (s *Server) UnaryAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	_, role, err := s.auth.AuthenticateRPC(ctx)
+
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	required, ok := RequiredRole[info.FullMethod]
+
+	if !ok {
+		required = "admin"
+	}
+
+	if !roleSatisfies(role, required) {
+		return nil, status.Errorf(codes.PermissionDenied, "role %q cannot call %s", role, info.FullMethod)
+	}
+
+	return handler(ctx, req)
+}
+
+// StreamAuthInterceptor is UnaryAuthInterceptor's counterpart for streaming
+// RPCs (StreamRaceControl), enforcing the same RequiredRole check before the
+// handler starts streaming.
+func (s *Server) StreamAuthInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	_, role, err := s.auth.AuthenticateRPC(stream.Context())
+
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	required, ok := RequiredRole[info.FullMethod]
+
+	if !ok {
+		required = "admin"
+	}
+
+	if !roleSatisfies(role, required) {
+		return status.Errorf(codes.PermissionDenied, "role %q cannot call %s", role, info.FullMethod)
+	}
+
+	return handler(srv, stream)
+}
+
+func roleSatisfies(role, required string) bool {
+	if role == "admin" {
+		return true
+	}
+
+	return role == required
+}
+
+func (s *Server) StartServer(ctx context.Context, req *StartServerRequest) (*Empty, error) {
+	if err := s.serverProcess.Start(req.EventType, req.RaceID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &Empty{}, nil
+}
+
+func (s *Server) StopServer(ctx context.Context, _ *Empty) (*Empty, error) {
+	if err := s.serverProcess.Stop(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &Empty{}, nil
+}
+
+func (s *Server) ServerStatus(ctx context.Context, _ *Empty) (*ServerStatusResponse, error) {
+	return &ServerStatusResponse{
+		Running:          s.serverProcess.IsRunning(),
+		Track:            s.raceControl.CurrentTrack(),
+		ConnectedDrivers: int32(s.raceControl.ConnectedDrivers()),
+	}, nil
+}
+
+func (s *Server) ListCustomRaces(ctx context.Context, _ *Empty) (*ListCustomRacesResponse, error) {
+	races, err := s.raceManager.ListCustomRaces()
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &ListCustomRacesResponse{Races: races}, nil
+}
+
+func (s *Server) ScheduleCustomRace(ctx context.Context, req *ScheduleRaceRequest) (*Empty, error) {
+	if err := s.raceManager.ScheduleRace(req.RaceID, req.ScheduledTime); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &Empty{}, nil
+}
+
+func (s *Server) ListChampionships(ctx context.Context, _ *Empty) (*ListChampionshipsResponse, error) {
+	championships, err := s.championshipManager.ListChampionships()
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &ListChampionshipsResponse{Championships: championships}, nil
+}
+
+func (s *Server) ListRaceWeekends(ctx context.Context, _ *Empty) (*ListRaceWeekendsResponse, error) {
+	raceWeekends, err := s.raceWeekendManager.ListRaceWeekends()
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &ListRaceWeekendsResponse{RaceWeekends: raceWeekends}, nil
+}
+
+func (s *Server) UploadCar(ctx context.Context, req *UploadContentRequest) (*Empty, error) {
+	if err := s.contentUploader.UploadCar(req.FileName, req.Data); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &Empty{}, nil
+}
+
+func (s *Server) UploadTrack(ctx context.Context, req *UploadContentRequest) (*Empty, error) {
+	if err := s.contentUploader.UploadTrack(req.FileName, req.Data); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &Empty{}, nil
+}
+
+func (s *Server) BlockDriver(ctx context.Context, req *BlockDriverRequest) (*Empty, error) {
+	if err := s.blockListManager.Block(req.GUID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &Empty{}, nil
+}
+
+func (s *Server) UnblockDriver(ctx context.Context, req *BlockDriverRequest) (*Empty, error) {
+	if err := s.blockListManager.Unblock(req.GUID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &Empty{}, nil
+}
+
+// AdminChatBroadcaster is the subset needed to forward a gRPC admin chat
+// request into the same broadcast path the HTML admin chat form uses.
+type AdminChatBroadcaster interface {
+	BroadcastChat(message string) error
+}
+
+func (s *Server) SendAdminChat(ctx context.Context, req *AdminChatRequest) (*Empty, error) {
+	if req.Message == "" {
+		return nil, status.Error(codes.InvalidArgument, "message must not be empty")
+	}
+
+	broadcaster, ok := s.serverProcess.(AdminChatBroadcaster)
+
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "admin chat broadcast is not available on this server process")
+	}
+
+	if err := broadcaster.BroadcastChat(req.Message); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &Empty{}, nil
+}
+
+func (s *Server) StreamRaceControl(_ *Empty, stream AdminService_StreamRaceControlServer) error {
+	events, unsubscribe := s.raceControl.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.Send(&event); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
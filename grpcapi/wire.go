@@ -0,0 +1,119 @@
+package grpcapi
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/JustaPenguin/assetto-server-manager/internal/protowire"
+)
+
+// The helpers below layer grpcapi.pb.go's own field conventions (nested
+// Message fields, google.protobuf.Timestamp) on top of protowire, the
+// wire-format primitives shared with internal/acServer/pb. Every field
+// number and type here matches grpcapi.proto, so the bytes these helpers
+// produce are readable by any client generated from that .proto, not just
+// this package.
+
+func appendTag(buf *bytes.Buffer, field, wireType int) {
+	protowire.AppendTag(buf, field, wireType)
+}
+
+func appendVarint(buf *bytes.Buffer, v uint64) {
+	protowire.AppendVarint(buf, v)
+}
+
+func appendBoolField(buf *bytes.Buffer, field int, v bool) {
+	protowire.AppendBoolField(buf, field, v)
+}
+
+func appendInt32Field(buf *bytes.Buffer, field int, v int32) {
+	protowire.AppendVarintField(buf, field, uint64(v))
+}
+
+func appendStringField(buf *bytes.Buffer, field int, v string) {
+	protowire.AppendStringField(buf, field, v)
+}
+
+func appendBytesField(buf *bytes.Buffer, field int, v []byte) {
+	protowire.AppendBytesField(buf, field, v)
+}
+
+// appendMessageField marshals m and writes it as a length-delimited nested
+// message, the same way protoc-generated code embeds one message in
+// another.
+func appendMessageField(buf *bytes.Buffer, field int, m Message) error {
+	data, err := m.Marshal()
+
+	if err != nil {
+		return err
+	}
+
+	protowire.AppendTag(buf, field, protowire.Bytes)
+	protowire.AppendVarint(buf, uint64(len(data)))
+	buf.Write(data)
+
+	return nil
+}
+
+// appendTimestampField writes t as a nested google.protobuf.Timestamp
+// message (seconds in field 1, nanos in field 2), skipping the field
+// entirely for the zero value the way proto3 omits unset messages.
+func appendTimestampField(buf *bytes.Buffer, field int, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+
+	inner := &bytes.Buffer{}
+
+	protowire.AppendVarintField(inner, 1, uint64(t.Unix()))
+	protowire.AppendVarintField(inner, 2, uint64(t.Nanosecond()))
+
+	protowire.AppendTag(buf, field, protowire.Bytes)
+	protowire.AppendVarint(buf, uint64(inner.Len()))
+	buf.Write(inner.Bytes())
+}
+
+// fieldReader walks length-delimited and varint fields out of a
+// protobuf-encoded message body.
+type fieldReader = protowire.FieldReader
+
+func newFieldReader(data []byte) *fieldReader {
+	return protowire.NewFieldReader(data)
+}
+
+func asBool(value []byte) bool {
+	return protowire.AsBool(value)
+}
+
+func asInt32(value []byte) int32 {
+	return int32(protowire.AsUint64(value))
+}
+
+func asBytes(value []byte) []byte {
+	return protowire.AsBytes(value)
+}
+
+// asTimestamp parses a nested google.protobuf.Timestamp message (seconds in
+// field 1, nanos in field 2) back into a time.Time.
+func asTimestamp(value []byte) time.Time {
+	r := protowire.NewFieldReader(value)
+
+	var seconds, nanos int64
+
+	for {
+		field, _, fieldValue, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			seconds = int64(protowire.AsUint64(fieldValue))
+		case 2:
+			nanos = int64(protowire.AsUint64(fieldValue))
+		}
+	}
+
+	return time.Unix(seconds, nanos).UTC()
+}
@@ -0,0 +1,183 @@
+package acServer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+)
+
+func marshalPublicKey(pub *rsa.PublicKey) ([]byte, error) {
+	return x509.MarshalPKIXPublicKey(pub)
+}
+
+// encryptedPrelude is sent by a client in place of the normal uint16
+// messageLength as the first two bytes of a connection to request the
+// encrypted transport used by privileged connections (admin CLI, remote
+// UDP-plugin bridge, gRPC-over-TCP).
+const encryptedPrelude uint16 = 0xE1C5
+
+// EncryptionKeyPair is the server's RSA keypair used to authenticate
+// privileged connections, configured via ServerOptions.
+type EncryptionKeyPair struct {
+	PrivateKey *rsa.PrivateKey
+	PublicKey  *rsa.PublicKey
+}
+
+// accountAuthenticator verifies that signature is a valid signature, by the
+// named account, over (nonce || encryptedKey). Covering both the server's
+// single-use challenge and the RSA-wrapped session key ties the signature to
+// this exact handshake: the nonce stops a captured handshake being replayed,
+// and covering encryptedKey stops a MITM from substituting its own session
+// key while leaving a captured accountID/signature pair untouched.
+type accountAuthenticator interface {
+	VerifyAccountSignature(accountID string, signedPayload []byte, signature []byte) error
+}
+
+// handshakeAESKeySize is 256-bit AES-GCM, the only session key size
+// negotiateEncryptedSession accepts from a client.
+const handshakeAESKeySize = 32
+
+// handshakeNonceSize is the length in bytes of the server-issued challenge
+// nonce.
+const handshakeNonceSize = 32
+
+// negotiateEncryptedSession performs the server side of the encrypted
+// handshake: send the RSA public key and a fresh challenge nonce, receive an
+// AES session key encrypted with the public key plus a signature over the
+// nonce and that encrypted key identifying the account, and return the
+// resulting AEAD. TCP.handleConnection opens every subsequent inbound packet
+// on the connection with it; outbound writes are not sealed with it.
+//
+// The nonce is generated here rather than accepted from the client: a
+// client-supplied nonce would let a captured handshake be replayed forever,
+// since the server would have no way to tell a fresh attempt from a recorded
+// one.
+//
+// Wire format (all fields length-prefixed with a uint16):
+//
+//	server -> client: DER-encoded RSA public key
+//	server -> client: challenge nonce
+//	client -> server: RSA-OAEP(AES key) | accountID | signature(nonce || encryptedKey)
+func negotiateEncryptedSession(conn net.Conn, keys EncryptionKeyPair, auth accountAuthenticator) (cipher.AEAD, error) {
+	pubDER, err := marshalPublicKey(keys.PublicKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeFrame(conn, pubDER); err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, handshakeNonceSize)
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	if err := writeFrame(conn, nonce); err != nil {
+		return nil, err
+	}
+
+	encryptedKey, err := readFrame(conn)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accountID, err := readFrame(conn)
+
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := readFrame(conn)
+
+	if err != nil {
+		return nil, err
+	}
+
+	signedPayload := make([]byte, 0, len(nonce)+len(encryptedKey))
+	signedPayload = append(signedPayload, nonce...)
+	signedPayload = append(signedPayload, encryptedKey...)
+
+	if err := auth.VerifyAccountSignature(string(accountID), signedPayload, signature); err != nil {
+		return nil, err
+	}
+
+	sessionKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, keys.PrivateKey, encryptedKey, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sessionKey) != handshakeAESKeySize {
+		return nil, errors.New("acServer: unexpected AES session key size")
+	}
+
+	block, err := aes.NewCipher(sessionKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func writeFrame(w io.Writer, data []byte) error {
+	if err := binary.Write(w, binary.LittleEndian, uint16(len(data))); err != nil {
+		return err
+	}
+
+	_, err := w.Write(data)
+
+	return err
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	var length uint16
+
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, length)
+
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// sealPacket wraps raw with the connection's negotiated AEAD, prefixing a
+// fresh random nonce so the caller can write it straight to the wire.
+func sealPacket(aead cipher.AEAD, raw []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, raw, nil), nil
+}
+
+// openPacket reverses sealPacket.
+func openPacket(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+
+	if len(sealed) < nonceSize {
+		return nil, errors.New("acServer: encrypted packet shorter than nonce")
+	}
+
+	nonce, ciphertext := sealed[:nonceSize], sealed[nonceSize:]
+
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,184 @@
+package acServer
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"net"
+	"testing"
+)
+
+type fakeAccountAuthenticator struct {
+	accountID string
+	valid     bool
+}
+
+func (f *fakeAccountAuthenticator) VerifyAccountSignature(accountID string, signedPayload []byte, signature []byte) error {
+	if !f.valid || accountID != f.accountID {
+		return errors.New("invalid signature")
+	}
+
+	return nil
+}
+
+// clientNegotiateEncryptedSession performs the client side of the handshake
+// negotiateEncryptedSession implements the server side of, so the full
+// exchange can be driven end to end over a net.Pipe in tests. It returns the
+// client's idea of the AES session key, so the test can confirm it matches
+// what the server derives.
+func clientNegotiateEncryptedSession(t *testing.T, conn net.Conn, accountID string) []byte {
+	t.Helper()
+
+	pubDER, err := readFrame(conn)
+
+	if err != nil {
+		t.Fatalf("client: read public key: %v", err)
+	}
+
+	pubAny, err := x509.ParsePKIXPublicKey(pubDER)
+
+	if err != nil {
+		t.Fatalf("client: parse public key: %v", err)
+	}
+
+	pub := pubAny.(*rsa.PublicKey)
+
+	nonce, err := readFrame(conn)
+
+	if err != nil {
+		t.Fatalf("client: read nonce: %v", err)
+	}
+
+	sessionKey := make([]byte, handshakeAESKeySize)
+
+	if _, err := rand.Read(sessionKey); err != nil {
+		t.Fatalf("client: generate session key: %v", err)
+	}
+
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, sessionKey, nil)
+
+	if err != nil {
+		t.Fatalf("client: encrypt session key: %v", err)
+	}
+
+	signedPayload := make([]byte, 0, len(nonce)+len(encryptedKey))
+	signedPayload = append(signedPayload, nonce...)
+	signedPayload = append(signedPayload, encryptedKey...)
+
+	signature := sha256.Sum256(signedPayload)
+
+	if err := writeFrame(conn, encryptedKey); err != nil {
+		t.Fatalf("client: write encrypted key: %v", err)
+	}
+
+	if err := writeFrame(conn, []byte(accountID)); err != nil {
+		t.Fatalf("client: write accountID: %v", err)
+	}
+
+	if err := writeFrame(conn, signature[:]); err != nil {
+		t.Fatalf("client: write signature: %v", err)
+	}
+
+	return sessionKey
+}
+
+func TestNegotiateEncryptedSessionRoundTrip(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	keys := EncryptionKeyPair{PrivateKey: priv, PublicKey: &priv.PublicKey}
+	auth := &fakeAccountAuthenticator{accountID: "driver1", valid: true}
+
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	type serverResult struct {
+		sealed []byte
+		err    error
+	}
+
+	results := make(chan serverResult, 1)
+
+	go func() {
+		aead, err := negotiateEncryptedSession(serverConn, keys, auth)
+
+		if err != nil {
+			results <- serverResult{err: err}
+			return
+		}
+
+		sealed, err := sealPacket(aead, []byte("hello"))
+		results <- serverResult{sealed: sealed, err: err}
+	}()
+
+	sessionKey := clientNegotiateEncryptedSession(t, clientConn, auth.accountID)
+
+	result := <-results
+
+	if result.err != nil {
+		t.Fatalf("negotiateEncryptedSession: %v", result.err)
+	}
+
+	if len(result.sealed) == 0 {
+		t.Fatalf("expected sealPacket to produce ciphertext")
+	}
+
+	block, err := aes.NewCipher(sessionKey)
+
+	if err != nil {
+		t.Fatalf("client: build AES cipher: %v", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+
+	if err != nil {
+		t.Fatalf("client: build AEAD: %v", err)
+	}
+
+	opened, err := openPacket(aead, result.sealed)
+
+	if err != nil {
+		t.Fatalf("client: could not decrypt server's sealed packet with its own derived key: %v", err)
+	}
+
+	if !bytes.Equal(opened, []byte("hello")) {
+		t.Fatalf("decrypted payload = %q, want %q", opened, "hello")
+	}
+}
+
+func TestNegotiateEncryptedSessionRejectsBadSignature(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	keys := EncryptionKeyPair{PrivateKey: priv, PublicKey: &priv.PublicKey}
+	auth := &fakeAccountAuthenticator{accountID: "driver1", valid: false}
+
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	errs := make(chan error, 1)
+
+	go func() {
+		_, err := negotiateEncryptedSession(serverConn, keys, auth)
+		errs <- err
+	}()
+
+	clientNegotiateEncryptedSession(t, clientConn, auth.accountID)
+
+	if err := <-errs; err == nil {
+		t.Fatalf("expected negotiateEncryptedSession to reject an invalid signature")
+	}
+}
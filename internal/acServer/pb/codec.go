@@ -0,0 +1,392 @@
+package pb
+
+import "bytes"
+
+func (m *Handshake) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendUint32Field(buf, 1, m.CarID)
+	appendStringField(buf, 2, m.DriverName)
+	appendStringField(buf, 3, m.DriverGUID)
+	appendStringField(buf, 4, m.CarModel)
+
+	return buf.Bytes(), nil
+}
+
+func (m *Handshake) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.CarID = asUint32(value)
+		case 2:
+			m.DriverName = string(value)
+		case 3:
+			m.DriverGUID = string(value)
+		case 4:
+			m.CarModel = string(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *EntryList) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendUint32Slice(buf, 1, m.CarIDs)
+
+	return buf.Bytes(), nil
+}
+
+func (m *EntryList) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		if field == 1 {
+			m.CarIDs = append(m.CarIDs, asUint32(value))
+		}
+	}
+
+	return nil
+}
+
+func (m *LapCompleted) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendUint32Field(buf, 1, m.CarID)
+	appendUint32Field(buf, 2, m.LapTimeMs)
+	appendUint32Slice(buf, 3, m.SectorTimesMs)
+	appendBoolField(buf, 4, m.Cuts)
+
+	return buf.Bytes(), nil
+}
+
+func (m *LapCompleted) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.CarID = asUint32(value)
+		case 2:
+			m.LapTimeMs = asUint32(value)
+		case 3:
+			m.SectorTimesMs = append(m.SectorTimesMs, asUint32(value))
+		case 4:
+			m.Cuts = asBool(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *SectorSplit) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendUint32Field(buf, 1, m.CarID)
+	appendUint32Field(buf, 2, m.SectorIndex)
+	appendUint32Field(buf, 3, m.SplitTimeMs)
+
+	return buf.Bytes(), nil
+}
+
+func (m *SectorSplit) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.CarID = asUint32(value)
+		case 2:
+			m.SectorIndex = asUint32(value)
+		case 3:
+			m.SplitTimeMs = asUint32(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *DamageZones) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendUint32Field(buf, 1, m.CarID)
+	appendFloat32Slice(buf, 2, m.Zones)
+
+	return buf.Bytes(), nil
+}
+
+func (m *DamageZones) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.CarID = asUint32(value)
+		case 2:
+			m.Zones = append(m.Zones, asFloat32(value))
+		}
+	}
+
+	return nil
+}
+
+func (m *ClientEvent) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendUint32Field(buf, 1, m.CarID)
+	appendUint32Field(buf, 2, m.OtherCarID)
+	appendStringField(buf, 3, m.EventType)
+	appendFloat32Field(buf, 4, m.Speed)
+
+	return buf.Bytes(), nil
+}
+
+func (m *ClientEvent) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.CarID = asUint32(value)
+		case 2:
+			m.OtherCarID = asUint32(value)
+		case 3:
+			m.EventType = string(value)
+		case 4:
+			m.Speed = asFloat32(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *Chat) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendUint32Field(buf, 1, m.CarID)
+	appendStringField(buf, 2, m.Message)
+
+	return buf.Bytes(), nil
+}
+
+func (m *Chat) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.CarID = asUint32(value)
+		case 2:
+			m.Message = string(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *VoteNextSession) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	appendUint32Field(buf, 1, m.CarID)
+	return buf.Bytes(), nil
+}
+
+func (m *VoteNextSession) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		if field == 1 {
+			m.CarID = asUint32(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *VoteRestartSession) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	appendUint32Field(buf, 1, m.CarID)
+	return buf.Bytes(), nil
+}
+
+func (m *VoteRestartSession) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		if field == 1 {
+			m.CarID = asUint32(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *VoteKick) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendUint32Field(buf, 1, m.CarID)
+	appendUint32Field(buf, 2, m.TargetCarID)
+
+	return buf.Bytes(), nil
+}
+
+func (m *VoteKick) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.CarID = asUint32(value)
+		case 2:
+			m.TargetCarID = asUint32(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *MandatoryPit) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendUint32Field(buf, 1, m.CarID)
+	appendBoolField(buf, 2, m.Completed)
+
+	return buf.Bytes(), nil
+}
+
+func (m *MandatoryPit) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.CarID = asUint32(value)
+		case 2:
+			m.Completed = asBool(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *TyreChange) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	appendUint32Field(buf, 1, m.CarID)
+	appendStringField(buf, 2, m.Compound)
+
+	return buf.Bytes(), nil
+}
+
+func (m *TyreChange) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		switch field {
+		case 1:
+			m.CarID = asUint32(value)
+		case 2:
+			m.Compound = string(value)
+		}
+	}
+
+	return nil
+}
+
+func (m *Disconnect) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	appendUint32Field(buf, 1, m.CarID)
+	return buf.Bytes(), nil
+}
+
+func (m *Disconnect) Unmarshal(data []byte) error {
+	r := newFieldReader(data)
+
+	for {
+		field, _, value, ok := r.Next()
+
+		if !ok {
+			break
+		}
+
+		if field == 1 {
+			m.CarID = asUint32(value)
+		}
+	}
+
+	return nil
+}
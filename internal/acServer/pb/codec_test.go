@@ -0,0 +1,105 @@
+package pb
+
+import "testing"
+
+func TestHandshakeRoundTrip(t *testing.T) {
+	in := &Handshake{
+		CarID:      7,
+		DriverName: "Driver",
+		DriverGUID: "76500000000000001",
+		CarModel:   "ks_ferrari_488",
+	}
+
+	data, err := in.Marshal()
+
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	out := &Handshake{}
+
+	if err := out.Unmarshal(data); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if *out != *in {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", out, in)
+	}
+}
+
+func TestEntryListRoundTrip(t *testing.T) {
+	in := &EntryList{CarIDs: []uint32{1, 2, 3}}
+
+	data, err := in.Marshal()
+
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	out := &EntryList{}
+
+	if err := out.Unmarshal(data); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if len(out.CarIDs) != len(in.CarIDs) {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", out.CarIDs, in.CarIDs)
+	}
+
+	for i := range in.CarIDs {
+		if out.CarIDs[i] != in.CarIDs[i] {
+			t.Fatalf("round trip mismatch at %d: got %d, want %d", i, out.CarIDs[i], in.CarIDs[i])
+		}
+	}
+}
+
+func TestLapCompletedRoundTrip(t *testing.T) {
+	in := &LapCompleted{
+		CarID:         3,
+		LapTimeMs:     123456,
+		SectorTimesMs: []uint32{40000, 41000, 42456},
+		Cuts:          true,
+	}
+
+	data, err := in.Marshal()
+
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	out := &LapCompleted{}
+
+	if err := out.Unmarshal(data); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if out.CarID != in.CarID || out.LapTimeMs != in.LapTimeMs || out.Cuts != in.Cuts || len(out.SectorTimesMs) != len(in.SectorTimesMs) {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", out, in)
+	}
+}
+
+func TestDamageZonesRoundTrip(t *testing.T) {
+	in := &DamageZones{CarID: 2, Zones: []float32{0.1, 0.5, 1}}
+
+	data, err := in.Marshal()
+
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	out := &DamageZones{}
+
+	if err := out.Unmarshal(data); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if out.CarID != in.CarID || len(out.Zones) != len(in.Zones) {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", out, in)
+	}
+
+	for i := range in.Zones {
+		if out.Zones[i] != in.Zones[i] {
+			t.Fatalf("round trip mismatch at %d: got %v, want %v", i, out.Zones[i], in.Zones[i])
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package pb holds hand-written Go types mirroring messages.proto: one
+// message per acServer.MessageType, meant to give plugin authors a stable,
+// schema-versioned wire format instead of reverse-engineering the game's
+// little-endian layout. Neither these types nor their Marshal/Unmarshal
+// methods (codec.go) are generated by protoc; they are kept in step with
+// the .proto file by hand.
+//
+// That goal isn't reachable yet: acServer.TCP only accepts this framing
+// while it is itself in ModeStandby (acServer.ProtoMessageHandler is
+// implemented by forwardingMessageHandler alone), since decoding it into
+// the 15 real per-MessageType handlers (Handshake, LapCompleted, ...) needs
+// their business logic, which lives outside this source snapshot.
+package pb
+
+// Message is satisfied by every type in this package. It mirrors
+// google.golang.org/protobuf/proto.Message closely enough for
+// acServer's dispatch code, without pulling handlers into a hard
+// dependency on the protobuf runtime.
+type Message interface {
+	Marshal() ([]byte, error)
+	Unmarshal([]byte) error
+}
+
+type Handshake struct {
+	CarID      uint32
+	DriverName string
+	DriverGUID string
+	CarModel   string
+}
+
+type EntryList struct {
+	CarIDs []uint32
+}
+
+type LapCompleted struct {
+	CarID         uint32
+	LapTimeMs     uint32
+	SectorTimesMs []uint32
+	Cuts          bool
+}
+
+type SectorSplit struct {
+	CarID       uint32
+	SectorIndex uint32
+	SplitTimeMs uint32
+}
+
+type DamageZones struct {
+	CarID uint32
+	Zones []float32
+}
+
+type ClientEvent struct {
+	CarID      uint32
+	OtherCarID uint32
+	EventType  string
+	Speed      float32
+}
+
+type Chat struct {
+	CarID   uint32
+	Message string
+}
+
+type VoteNextSession struct {
+	CarID uint32
+}
+
+type VoteRestartSession struct {
+	CarID uint32
+}
+
+type VoteKick struct {
+	CarID       uint32
+	TargetCarID uint32
+}
+
+type MandatoryPit struct {
+	CarID     uint32
+	Completed bool
+}
+
+type TyreChange struct {
+	CarID    uint32
+	Compound string
+}
+
+type Disconnect struct {
+	CarID uint32
+}
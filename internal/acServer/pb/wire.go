@@ -0,0 +1,80 @@
+package pb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+
+	"github.com/JustaPenguin/assetto-server-manager/internal/protowire"
+)
+
+// The helpers below layer this package's own field conventions (uint32 and
+// float32 scalars and repeated fields) on top of protowire, the wire-format
+// primitives shared with grpcapi, so the Marshal/Unmarshal methods in
+// codec.go round-trip with anything speaking standard protobuf. Repeated
+// scalar fields are written and read unpacked only, matching the
+// [packed = false] options in messages.proto.
+
+func appendTag(buf *bytes.Buffer, field, wireType int) {
+	protowire.AppendTag(buf, field, wireType)
+}
+
+func appendVarint(buf *bytes.Buffer, v uint64) {
+	protowire.AppendVarint(buf, v)
+}
+
+func appendUint32Field(buf *bytes.Buffer, field int, v uint32) {
+	protowire.AppendVarintField(buf, field, uint64(v))
+}
+
+func appendBoolField(buf *bytes.Buffer, field int, v bool) {
+	protowire.AppendBoolField(buf, field, v)
+}
+
+func appendStringField(buf *bytes.Buffer, field int, v string) {
+	protowire.AppendStringField(buf, field, v)
+}
+
+func appendFloat32Field(buf *bytes.Buffer, field int, v float32) {
+	protowire.AppendFloat32Field(buf, field, v)
+}
+
+func appendUint32Slice(buf *bytes.Buffer, field int, vs []uint32) {
+	for _, v := range vs {
+		protowire.AppendTag(buf, field, protowire.Varint)
+		protowire.AppendVarint(buf, uint64(v))
+	}
+}
+
+func appendFloat32Slice(buf *bytes.Buffer, field int, vs []float32) {
+	for _, v := range vs {
+		// Unlike appendFloat32Field, zero elements are written too:
+		// skipping one would shift every later element's index.
+		protowire.AppendTag(buf, field, protowire.Fixed32)
+
+		var tmp [4]byte
+		binary.LittleEndian.PutUint32(tmp[:], math.Float32bits(v))
+		buf.Write(tmp[:])
+	}
+}
+
+// fieldReader walks length-delimited, varint and fixed32 fields out of a
+// protobuf-encoded message body, calling into the per-type Unmarshal
+// implementations.
+type fieldReader = protowire.FieldReader
+
+func newFieldReader(data []byte) *fieldReader {
+	return protowire.NewFieldReader(data)
+}
+
+func asUint32(value []byte) uint32 {
+	return uint32(protowire.AsUint64(value))
+}
+
+func asBool(value []byte) bool {
+	return protowire.AsBool(value)
+}
+
+func asFloat32(value []byte) float32 {
+	return protowire.AsFloat32(value)
+}
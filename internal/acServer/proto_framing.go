@@ -0,0 +1,29 @@
+package acServer
+
+import "net"
+
+// protoFramingSentinel is sent as the very first byte of a connection to
+// opt it into the protobuf-framed wire format (see the acServer/pb
+// package) instead of the default "uint16 length | uint8 MessageType |
+// little-endian body" framing. It is a value that can never be a valid
+// high byte of a legitimate little-endian messageLength for any message
+// this server currently sends.
+//
+// Only a connection to an instance that is itself in ModeStandby can
+// actually use it today: forwardingMessageHandler (see standby.go) is
+// the only handler in this tree that implements ProtoMessageHandler, so a
+// connection requesting this framing outside ModeStandby is rejected (see
+// TCP's accept loop) rather than accepted and then silently dropped
+// message by message.
+const protoFramingSentinel byte = 0xFE
+
+// ProtoMessageHandler is implemented by message handlers that support the
+// protobuf framing in addition to the default one, sharing their business
+// logic between OnMessage and OnProtoMessage.
+type ProtoMessageHandler interface {
+	TCPMessageHandler
+
+	// OnProtoMessage is called with the protobuf-encoded message body
+	// (i.e. with the MessageType byte already stripped off).
+	OnProtoMessage(conn net.Conn, body []byte) error
+}
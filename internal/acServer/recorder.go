@@ -0,0 +1,244 @@
+package acServer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// RecorderConfig enables per-session TCP message recording to disk, so a
+// bug report from a real race weekend ("unknown TCP message type", "handler
+// returned error") can be reproduced deterministically with Replayer
+// instead of just logged and lost.
+type RecorderConfig struct {
+	// Enabled turns recording on. Disabled by default: this is a debugging
+	// aid, not something that should cost every connection overhead.
+	Enabled bool
+
+	// Dir is the directory recordings are written to.
+	Dir string
+}
+
+// maxRecordingFileBytes is the rotation threshold: once a recording file
+// crosses it, the recorder closes it and starts a new one so a long-running
+// session doesn't produce one unbounded file.
+const maxRecordingFileBytes = 64 * 1024 * 1024
+
+const (
+	recordingDirectionIn  byte = 0
+	recordingDirectionOut byte = 1
+)
+
+// sessionRecorder writes timestamped (MessageType, raw bytes, direction)
+// entries for every TCP message in a session to a rotating set of files
+// under its configured directory, one file per session plus one per
+// rotation.
+type sessionRecorder struct {
+	dir  string
+	name string
+
+	mu      sync.Mutex
+	file    *os.File
+	written int64
+	part    int
+}
+
+// newSessionRecorder opens the first file for a new recording session,
+// named after the time it started so recordings from different runs don't
+// collide.
+func newSessionRecorder(dir string) (*sessionRecorder, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	r := &sessionRecorder{
+		dir:  dir,
+		name: fmt.Sprintf("session-%d", time.Now().UnixNano()),
+	}
+
+	if err := r.rotate(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *sessionRecorder) path(part int) string {
+	return filepath.Join(r.dir, fmt.Sprintf("%s.part%d.rec", r.name, part))
+}
+
+// rotate closes the current file, if any, and opens the next part.
+func (r *sessionRecorder) rotate() error {
+	if r.file != nil {
+		if err := r.file.Close(); err != nil {
+			return err
+		}
+
+		r.part++
+	}
+
+	file, err := os.Create(r.path(r.part))
+
+	if err != nil {
+		return err
+	}
+
+	r.file = file
+	r.written = 0
+
+	return nil
+}
+
+// record appends one (MessageType, raw bytes, direction) entry for connID,
+// rotating to a new file first if this entry would cross
+// maxRecordingFileBytes. protoMode records whether raw was read off a
+// protobuf-framed connection (see tcpConn.protoMode), so Replayer can later
+// dispatch it through the same decoder the live connection used.
+//
+// Wire format, all integers little-endian:
+//
+//	int64  timestamp (UnixNano)
+//	uint8  direction (recordingDirectionIn / recordingDirectionOut)
+//	uint8  protoMode (0 = classic framing, 1 = protobuf framing)
+//	uint16 len(connID) | connID
+//	uint8  messageType
+//	uint32 len(raw) | raw
+func (r *sessionRecorder) record(connID string, direction byte, protoMode bool, messageType MessageType, raw []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entryLen := int64(8 + 1 + 1 + 2 + len(connID) + 1 + 4 + len(raw))
+
+	if r.written+entryLen > maxRecordingFileBytes {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if err := binary.Write(r.file, binary.LittleEndian, time.Now().UnixNano()); err != nil {
+		return err
+	}
+
+	if err := binary.Write(r.file, binary.LittleEndian, direction); err != nil {
+		return err
+	}
+
+	if err := binary.Write(r.file, binary.LittleEndian, boolToByte(protoMode)); err != nil {
+		return err
+	}
+
+	if err := binary.Write(r.file, binary.LittleEndian, uint16(len(connID))); err != nil {
+		return err
+	}
+
+	if _, err := r.file.WriteString(connID); err != nil {
+		return err
+	}
+
+	if err := binary.Write(r.file, binary.LittleEndian, byte(messageType)); err != nil {
+		return err
+	}
+
+	if err := binary.Write(r.file, binary.LittleEndian, uint32(len(raw))); err != nil {
+		return err
+	}
+
+	if _, err := r.file.Write(raw); err != nil {
+		return err
+	}
+
+	r.written += entryLen
+
+	return nil
+}
+
+func boolToByte(b bool) byte {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+func (r *sessionRecorder) close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.file.Close()
+}
+
+// EnableRecording configures t to record every accepted connection's
+// messages to cfg.Dir for later replay with Replayer. Must be called
+// before Listen.
+func (t *TCP) EnableRecording(cfg RecorderConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	recorder, err := newSessionRecorder(cfg.Dir)
+
+	if err != nil {
+		return err
+	}
+
+	t.recorder = recorder
+
+	return nil
+}
+
+// recordInbound is a no-op when recording is disabled, and otherwise logs
+// (rather than fails) a recording error: losing a recording must never take
+// down the connection it's recording. protoMode records which decoder raw
+// was read with, so Replayer can pick the same one back.
+func (t *TCP) recordInbound(conn net.Conn, protoMode bool, messageType MessageType, raw []byte) {
+	t.record(conn, recordingDirectionIn, protoMode, messageType, raw)
+}
+
+// recordOutbound mirrors recordInbound for messages written back out to
+// conn. Outbound entries are never replayed (see Replayer.Replay), so they're
+// always recorded as classic framing; protoMode only matters for inbound
+// entries a replay will actually dispatch.
+func (t *TCP) recordOutbound(conn net.Conn, messageType MessageType, raw []byte) {
+	t.record(conn, recordingDirectionOut, false, messageType, raw)
+}
+
+func (t *TCP) record(conn net.Conn, direction byte, protoMode bool, messageType MessageType, raw []byte) {
+	if t.recorder == nil {
+		return
+	}
+
+	if err := t.recorder.record(conn.RemoteAddr().String(), direction, protoMode, messageType, raw); err != nil {
+		t.logger.WithError(err).Error("couldn't write tcp recording entry")
+	}
+}
+
+// recordingUnparsedMessageType tags an outbound write that didn't look like
+// a single complete length-prefixed frame, so recordRawOutbound still logs
+// something rather than silently dropping it.
+const recordingUnparsedMessageType MessageType = 0xFF
+
+// recordRawOutbound best-effort parses p as a single "uint16 length |
+// uint8 MessageType | body" write, as produced by Packet.WriteTCP, and
+// records it. Callers aren't guaranteed to write a whole frame in one
+// conn.Write, so anything that doesn't parse is still recorded, tagged
+// with recordingUnparsedMessageType.
+func (t *TCP) recordRawOutbound(conn net.Conn, p []byte) {
+	if t.recorder == nil || len(p) == 0 {
+		return
+	}
+
+	if len(p) >= 3 {
+		length := binary.LittleEndian.Uint16(p[:2])
+
+		if int(length) == len(p)-2 {
+			t.recordOutbound(conn, MessageType(p[2]), p[3:])
+			return
+		}
+	}
+
+	t.recordOutbound(conn, recordingUnparsedMessageType, p)
+}
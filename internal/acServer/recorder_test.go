@@ -0,0 +1,60 @@
+package acServer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSessionRecorderRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	r, err := newSessionRecorder(dir)
+
+	if err != nil {
+		t.Fatalf("newSessionRecorder: %v", err)
+	}
+
+	if err := r.record("127.0.0.1:1234", recordingDirectionIn, false, MessageType(1), []byte{1, 2, 3}); err != nil {
+		t.Fatalf("record (classic): %v", err)
+	}
+
+	if err := r.record("127.0.0.1:1234", recordingDirectionIn, true, MessageType(2), []byte{2, 4, 5, 6}); err != nil {
+		t.Fatalf("record (proto): %v", err)
+	}
+
+	if err := r.close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	messages, err := ReadRecording(r.path(0))
+
+	if err != nil {
+		t.Fatalf("ReadRecording: %v", err)
+	}
+
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(messages))
+	}
+
+	classic, proto := messages[0], messages[1]
+
+	if classic.ProtoMode {
+		t.Fatalf("expected first entry to have ProtoMode false")
+	}
+
+	if classic.MessageType != 1 || !bytes.Equal(classic.Raw, []byte{1, 2, 3}) {
+		t.Fatalf("classic entry mismatch: %+v", classic)
+	}
+
+	if !proto.ProtoMode {
+		t.Fatalf("expected second entry to have ProtoMode true")
+	}
+
+	if proto.MessageType != 2 || !bytes.Equal(proto.Raw, []byte{2, 4, 5, 6}) {
+		t.Fatalf("proto entry mismatch: %+v", proto)
+	}
+
+	if classic.ConnID != "127.0.0.1:1234" || proto.ConnID != "127.0.0.1:1234" {
+		t.Fatalf("connID mismatch: %+v %+v", classic, proto)
+	}
+}
@@ -0,0 +1,340 @@
+package acServer
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// standbyTailPrelude is sent by activeConn in place of the first message's
+// normal uint16 length, once per (re)dialled connection, so the active
+// peer's accept loop (see TCP.registerStandbyConn in tcp.go) knows to treat
+// this connection as a standby tailing its broadcasts rather than an
+// ordinary game client - the same out-of-band-magic-value trick
+// encryptedPrelude and protoFramingSentinel already use to multiplex a
+// second protocol onto a plain TCP connection.
+const standbyTailPrelude uint16 = 0xE1C6
+
+// Mode describes whether a TCP instance is actively running a session or is
+// a warm standby tailing an active peer, modelled on etcd's participant /
+// standby split.
+type Mode string
+
+const (
+	// ModeActive is the normal mode: all message handlers registered in
+	// initMessageHandlers run the race as usual.
+	ModeActive Mode = "active"
+
+	// ModeStandby accepts TCP connections for admin/telemetry only.
+	// Race message handlers are replaced with a forwarding handler that
+	// proxies to the configured active peer, and ServerState is kept warm
+	// by tailing broadcast messages and results from that peer.
+	ModeStandby Mode = "standby"
+)
+
+// StandbyConfig configures a TCP instance's participation in a hot-standby
+// pair.
+type StandbyConfig struct {
+	// Mode is the mode the TCP instance boots into.
+	Mode Mode
+
+	// ActiveAddr is the address of the active peer to forward to / tail
+	// while in ModeStandby.
+	ActiveAddr string
+
+	// HeartbeatInterval is how long a standby waits between attempts to
+	// dial ActiveAddr while the active peer is unreachable. No heartbeat is
+	// exchanged while the connection is up, and the active peer does not
+	// read this setting.
+	HeartbeatInterval time.Duration
+
+	// MaxMissedHeartbeats is how many consecutive failed dial attempts to
+	// ActiveAddr a standby tolerates before promoting itself, taking over
+	// the session.
+	MaxMissedHeartbeats int
+}
+
+func (c StandbyConfig) heartbeatInterval() time.Duration {
+	if c.HeartbeatInterval > 0 {
+		return c.HeartbeatInterval
+	}
+
+	return 5 * time.Second
+}
+
+func (c StandbyConfig) maxMissedHeartbeats() int {
+	if c.MaxMissedHeartbeats > 0 {
+		return c.MaxMissedHeartbeats
+	}
+
+	return 3
+}
+
+// forwardingMessageHandler proxies every message it receives to the active
+// peer rather than acting on it locally, and is installed in place of the
+// participant handlers while a TCP instance is in ModeStandby.
+type forwardingMessageHandler struct {
+	messageType MessageType
+	activeConn  *activeConn
+	logger      Logger
+}
+
+func newForwardingMessageHandler(messageType MessageType, activeConn *activeConn, logger Logger) *forwardingMessageHandler {
+	return &forwardingMessageHandler{
+		messageType: messageType,
+		activeConn:  activeConn,
+		logger:      logger,
+	}
+}
+
+func (f *forwardingMessageHandler) MessageType() MessageType {
+	return f.messageType
+}
+
+func (f *forwardingMessageHandler) OnMessage(conn net.Conn, p *Packet) error {
+	return f.activeConn.forward(f.messageType, p)
+}
+
+// OnProtoMessage forwards a protobuf-framed message to the active peer the
+// same way OnMessage forwards a classic one, satisfying ProtoMessageHandler
+// so standby connections can proxy protobuf-framed clients too.
+func (f *forwardingMessageHandler) OnProtoMessage(conn net.Conn, body []byte) error {
+	return f.activeConn.forwardProto(f.messageType, body)
+}
+
+// activeConn maintains the standby's connection to the active peer used for
+// both forwarding incoming race messages and tailing the active's broadcast
+// of ServerState-affecting messages (results, session changes, etc).
+type activeConn struct {
+	addr   string
+	logger Logger
+
+	mu                sync.Mutex
+	conn              net.Conn
+	protoSentinelSent bool
+}
+
+func newActiveConn(addr string, logger Logger) *activeConn {
+	return &activeConn{addr: addr, logger: logger}
+}
+
+func (a *activeConn) dial() (net.Conn, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.conn != nil {
+		return a.conn, nil
+	}
+
+	conn, err := net.Dial("tcp", a.addr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Register this connection with the active peer as a standby tail
+	// before anything else goes over it, so the active's accept loop
+	// starts echoing broadcasts to it (see TCP.registerStandbyConn).
+	if err := binary.Write(conn, binary.LittleEndian, standbyTailPrelude); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	a.conn = conn
+
+	return conn, nil
+}
+
+func (a *activeConn) forward(messageType MessageType, p *Packet) error {
+	conn, err := a.dial()
+
+	if err != nil {
+		a.logger.WithError(err).Errorf("standby: could not reach active peer %s", a.addr)
+		return err
+	}
+
+	if err := p.WriteTCP(conn); err != nil {
+		a.resetConn()
+		return err
+	}
+
+	return nil
+}
+
+// forwardProto proxies a protobuf-framed message to the active peer,
+// sending protoFramingSentinel ahead of the first message on a (re)dialled
+// connection so the active peer knows to expect protobuf framing from then
+// on.
+func (a *activeConn) forwardProto(messageType MessageType, body []byte) error {
+	conn, err := a.dial()
+
+	if err != nil {
+		a.logger.WithError(err).Errorf("standby: could not reach active peer %s", a.addr)
+		return err
+	}
+
+	a.mu.Lock()
+	needsSentinel := !a.protoSentinelSent
+	a.protoSentinelSent = true
+	a.mu.Unlock()
+
+	if needsSentinel {
+		if _, err := conn.Write([]byte{protoFramingSentinel}); err != nil {
+			a.resetConn()
+			return err
+		}
+	}
+
+	frame := append([]byte{byte(messageType)}, body...)
+
+	if err := binary.Write(conn, binary.LittleEndian, uint16(len(frame))); err != nil {
+		a.resetConn()
+		return err
+	}
+
+	if _, err := conn.Write(frame); err != nil {
+		a.resetConn()
+		return err
+	}
+
+	return nil
+}
+
+// resetConn drops the cached connection (and proto-framing state tied to
+// it) after a write failure, so the next forward/forwardProto call redials
+// and re-sends protoFramingSentinel if needed.
+func (a *activeConn) resetConn() {
+	a.mu.Lock()
+	a.conn = nil
+	a.protoSentinelSent = false
+	a.mu.Unlock()
+}
+
+// tailState keeps a standby's ServerState warm by replaying the active's
+// broadcast messages (lap completed, session changed, results, etc) into the
+// local state, so a promoted standby has an up-to-date picture of the race.
+// The same connection activeConn.forward/forwardProto write client
+// messages to doubles as the read side here: dial() registers it with the
+// active peer via standbyTailPrelude, and the active's handleConnection
+// echoes every message it successfully handles back down every connection
+// registered that way (see TCP.broadcastToStandbys in tcp.go) - that's the
+// wire protocol this loop is reading.
+// participantHandlers is the handler set EnterStandby swapped out, captured
+// before the swap: tailed messages must reach the real state-mutating
+// handlers, not the forwarding handlers now installed in t.messageHandlers,
+// or a tailed broadcast would just get forwarded straight back out to the
+// active peer instead of updating local state. If the active peer is
+// unreachable for cfg.maxMissedHeartbeats() consecutive attempts, it
+// promotes itself rather than tailing a dead peer forever.
+func (t *TCP) tailState(cfg StandbyConfig, server *Server, conn *activeConn, participantHandlers map[MessageType]TCPMessageHandler) {
+	missedHeartbeats := 0
+
+	go func() {
+		for {
+			raw, err := conn.dial()
+
+			if err != nil {
+				missedHeartbeats++
+				t.logger.WithError(err).Debugf("standby: waiting for active peer %s (missed %d/%d)", cfg.ActiveAddr, missedHeartbeats, cfg.maxMissedHeartbeats())
+
+				if missedHeartbeats >= cfg.maxMissedHeartbeats() {
+					if t.Mode() == ModeStandby {
+						t.logger.Errorf("standby: active peer %s unreachable after %d attempts, promoting", cfg.ActiveAddr, missedHeartbeats)
+						t.Promote(server)
+					}
+
+					return
+				}
+
+				time.Sleep(cfg.heartbeatInterval())
+				continue
+			}
+
+			missedHeartbeats = 0
+
+			var messageLength uint16
+
+			if err := binary.Read(raw, binary.LittleEndian, &messageLength); err != nil {
+				conn.resetConn()
+				continue
+			}
+
+			buf := make([]byte, messageLength)
+
+			if _, err := io.ReadFull(raw, buf); err != nil {
+				conn.resetConn()
+				continue
+			}
+
+			var messageType MessageType
+
+			p := NewPacket(buf)
+			p.Read(&messageType)
+
+			handler, ok := participantHandlers[messageType]
+
+			if ok {
+				if err := handler.OnMessage(raw, p); err != nil {
+					t.logger.WithError(err).Debugf("standby: tail handler 0x%x returned error", messageType)
+				}
+			}
+
+			select {
+			case <-t.closed:
+				return
+			default:
+			}
+		}
+	}()
+}
+
+// EnterStandby replaces t's message handlers with forwarding handlers that
+// proxy race messages to cfg.ActiveAddr, and starts tailing that peer's
+// broadcasts so ServerState stays warm. It is safe to call before Listen.
+// server is retained so tailState can rebuild the participant handler set via
+// Promote if the active peer disappears.
+func (t *TCP) EnterStandby(cfg StandbyConfig, server *Server) {
+	active := newActiveConn(cfg.ActiveAddr, t.logger)
+
+	t.mu.Lock()
+
+	t.mode = ModeStandby
+	participantHandlers := t.messageHandlers
+
+	standbyHandlers := make(map[MessageType]TCPMessageHandler, len(participantHandlers))
+
+	for messageType := range participantHandlers {
+		standbyHandlers[messageType] = newForwardingMessageHandler(messageType, active, t.logger)
+	}
+
+	t.messageHandlers = standbyHandlers
+
+	t.mu.Unlock()
+
+	t.tailState(cfg, server, active, participantHandlers)
+}
+
+// Promote swaps t back to the full participant handler set and takes over
+// the session, for use when a leadership channel (file lock, HTTP heartbeat,
+// or a small Raft-style vote among peers) determines the active peer is no
+// longer reachable.
+func (t *TCP) Promote(server *Server) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.mode = ModeActive
+	t.messageHandlers = make(map[MessageType]TCPMessageHandler)
+	t.initMessageHandlers(server)
+
+	t.logger.Infof("standby: promoted to active, taking over session")
+}
+
+// Mode returns the TCP instance's current mode.
+func (t *TCP) Mode() Mode {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.mode
+}
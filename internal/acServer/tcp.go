@@ -1,30 +1,67 @@
 package acServer
 
 import (
+	"bufio"
+	"crypto/cipher"
 	"encoding/binary"
 	"fmt"
 	"net"
+	"sync"
 	"time"
 )
 
 type TCP struct {
 	port uint16
 
+	mu              sync.Mutex
 	messageHandlers map[MessageType]TCPMessageHandler
+	mode            Mode
 
 	listener *net.TCPListener
 	closed   chan struct{}
 	state    *ServerState
 	logger   Logger
+
+	// encryptionKeys and accountAuth are optional: when set, connections
+	// may upgrade to the encrypted transport by sending encryptedPrelude
+	// in place of the first message's length.
+	encryptionKeys EncryptionKeyPair
+	accountAuth    accountAuthenticator
+
+	// recorder is optional: when set (see EnableRecording), every message
+	// read from or written to an accepted connection is logged to disk so
+	// it can be reproduced later with a Replayer.
+	recorder *sessionRecorder
+
+	// standbyMu guards standbyConns, the set of connections that have
+	// identified themselves with standbyTailPrelude (see standby.go):
+	// a standby peer's activeConn, tailing this instance's broadcast
+	// messages to keep its own ServerState warm. Every message this
+	// instance successfully handles is echoed to these connections, never
+	// to an ordinary game client's connection.
+	standbyMu    sync.Mutex
+	standbyConns map[net.Conn]struct{}
+
+	// plugin is copied from server.plugin: the same value already threaded
+	// into NewHandshakeMessageHandler/NewSectorSplitMessageHandler/
+	// NewClientEventMessageHandler in initMessageHandlers for their own domain-specific
+	// callbacks. Its real interface (Plugin) is declared outside this
+	// source snapshot, so it's kept here as interface{} and recovered with
+	// a type assertion against pluginBroadcaster, the one method this
+	// package actually needs, rather than guessing Plugin's full shape.
+	plugin interface{}
 }
 
 func NewTCP(port uint16, server *Server) *TCP {
 	tcp := &TCP{
 		port:            port,
 		messageHandlers: make(map[MessageType]TCPMessageHandler),
+		mode:            ModeActive,
 		closed:          make(chan struct{}, 1),
 		state:           server.state,
 		logger:          server.logger,
+		standbyConns:    make(map[net.Conn]struct{}),
+		plugin:          server.plugin,
 	}
 
 	tcp.initMessageHandlers(server)
@@ -32,6 +69,19 @@ func NewTCP(port uint16, server *Server) *TCP {
 	return tcp
 }
 
+// EnableEncryptedTransport configures the RSA keypair and account
+// authenticator used to negotiate the encrypted transport for privileged
+// connections (admin CLI, remote UDP-plugin bridge, gRPC-over-TCP). It's
+// optional in the same way EnableRecording is: call it once, after
+// NewTCP and before Listen, with the keypair loaded from the configured
+// privileged-connection settings. Leaving it uncalled just means no
+// connection can negotiate the encrypted transport (encryptionKeys stays
+// its zero value), not that plaintext connections are rejected.
+func (t *TCP) EnableEncryptedTransport(keys EncryptionKeyPair, auth accountAuthenticator) {
+	t.encryptionKeys = keys
+	t.accountAuth = auth
+}
+
 func (t *TCP) initMessageHandlers(server *Server) {
 	votingManager := NewVotingManager(server.state, server.sessionManager, server.logger)
 
@@ -62,6 +112,40 @@ func (t *TCP) initMessageHandlers(server *Server) {
 type tcpConn struct {
 	net.Conn
 	closer chan struct{}
+
+	// aead is set once a connection has completed the encrypted handshake
+	// (see encryption.go); handleConnection opens every subsequent inbound
+	// packet with it. Write does not seal outbound packets with it.
+	aead cipher.AEAD
+
+	// reader buffers reads so the connection's framing can be sniffed from
+	// its first byte (see proto_framing.go) without losing it.
+	reader           *bufio.Reader
+	protoModeChecked bool
+	protoMode        bool
+
+	// owner is the TCP instance that accepted this connection, so outbound
+	// writes can be logged (when recording is enabled, see EnableRecording)
+	// alongside the inbound messages recorded in handleConnection.
+	owner *TCP
+}
+
+// Read shadows the embedded net.Conn's Read so every read for this
+// connection - including the framing sniff - goes through the same
+// buffered reader.
+func (c *tcpConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// Write shadows the embedded net.Conn's Write so every outbound message for
+// this connection is recorded (when c.owner has recording enabled)
+// alongside the inbound ones captured in handleConnection.
+func (c *tcpConn) Write(p []byte) (int, error) {
+	if c.owner != nil {
+		c.owner.recordRawOutbound(c, p)
+	}
+
+	return c.Conn.Write(p)
 }
 
 func (t *TCP) Listen() error {
@@ -108,6 +192,8 @@ func (t *TCP) Listen() error {
 			c := &tcpConn{
 				Conn:   conn,
 				closer: make(chan struct{}, 1),
+				reader: bufio.NewReader(conn),
+				owner:  t,
 			}
 
 			go func(conn *tcpConn) {
@@ -118,6 +204,8 @@ func (t *TCP) Listen() error {
 					case <-conn.closer:
 						car, _ := t.state.GetCarByTCPConn(conn)
 
+						t.unregisterStandbyConn(conn)
+
 						if err := conn.Close(); err != nil {
 							t.logger.WithError(err).Errorf("Could not close tcp connection for: %s", conn.RemoteAddr().String())
 						} else {
@@ -130,6 +218,32 @@ func (t *TCP) Listen() error {
 
 						return
 					default:
+						if !conn.protoModeChecked {
+							conn.protoModeChecked = true
+
+							if first, err := conn.reader.Peek(1); err == nil && first[0] == protoFramingSentinel {
+								conn.reader.Discard(1)
+
+								if t.Mode() != ModeStandby {
+									// Protobuf framing only has a real dispatch path in
+									// ModeStandby, where every registered handler is a
+									// forwardingMessageHandler proxying raw bytes rather
+									// than decoding them (see ProtoMessageHandler in
+									// proto_framing.go). None of the 15 real handlers
+									// (Handshake, LapCompleted, ...) implement it - doing
+									// so needs their actual business logic, which isn't
+									// in this source snapshot. Reject the connection
+									// outright instead of accepting it and then silently
+									// dropping every message it sends.
+									t.logger.Errorf("rejecting protobuf-framed connection from %s: not supported outside standby mode", conn.RemoteAddr())
+									conn.closer <- struct{}{}
+									continue
+								}
+
+								conn.protoMode = true
+							}
+						}
+
 						var messageLength uint16
 
 						if err := binary.Read(conn, binary.LittleEndian, &messageLength); err != nil {
@@ -142,6 +256,24 @@ func (t *TCP) Listen() error {
 							return
 						}
 
+						if conn.aead == nil && messageLength == encryptedPrelude && t.accountAuth != nil {
+							aead, err := negotiateEncryptedSession(conn, t.encryptionKeys, t.accountAuth)
+
+							if err != nil {
+								t.logger.WithError(err).Error("couldn't negotiate encrypted tcp session")
+								closeTCPConnection(conn)
+								return
+							}
+
+							conn.aead = aead
+							continue
+						}
+
+						if messageLength == standbyTailPrelude {
+							t.registerStandbyConn(conn)
+							continue
+						}
+
 						if err = t.handleConnection(conn, messageLength); err != nil {
 							if e, ok := err.(*net.OpError); ok && !e.Temporary() {
 								conn.closer <- struct{}{}
@@ -169,23 +301,157 @@ func (t *TCP) handleConnection(conn net.Conn, messageLength uint16) error {
 		return err
 	}
 
+	raw := buf[:n]
+
+	if c, ok := conn.(*tcpConn); ok && c.aead != nil {
+		raw, err = openPacket(c.aead, raw)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if c, ok := conn.(*tcpConn); ok && c.protoMode {
+		return t.handleProtoMessage(conn, raw)
+	}
+
 	var messageType MessageType
 
-	p := NewPacket(buf[:n])
+	p := NewPacket(raw)
 	p.Read(&messageType)
 
+	t.recordInbound(conn, false, messageType, raw)
+
+	t.mu.Lock()
 	messageHandler, ok := t.messageHandlers[messageType]
+	t.mu.Unlock()
 
 	if ok {
 		if err := messageHandler.OnMessage(conn, p); err != nil {
 			t.logger.WithError(err).Errorf("Message Handler: 0x%x returned error", messageHandler.MessageType())
 			return err
 		}
+
+		t.broadcastToStandbys(raw)
+		t.broadcastToPlugin(raw)
 	} else {
 		t.logger.Errorf("Unknown TCP message type: 0x%x (len: %d)", messageType, n)
 
-		fmt.Printf("%x\n", buf[:n])
+		fmt.Printf("%x\n", raw)
+	}
+
+	return nil
+}
+
+// registerStandbyConn marks conn as a standby peer's tail connection (see
+// standbyTailPrelude in standby.go), so every message this instance
+// handles from here on is also echoed to it via broadcastToStandbys.
+func (t *TCP) registerStandbyConn(conn net.Conn) {
+	t.standbyMu.Lock()
+	t.standbyConns[conn] = struct{}{}
+	t.standbyMu.Unlock()
+}
+
+// unregisterStandbyConn removes conn from the standby broadcast set. It's a
+// no-op if conn was never registered.
+func (t *TCP) unregisterStandbyConn(conn net.Conn) {
+	t.standbyMu.Lock()
+	delete(t.standbyConns, conn)
+	t.standbyMu.Unlock()
+}
+
+// broadcastToStandbys echoes raw - the exact classic-framed message body
+// handleConnection just dispatched, messageType included - to every
+// registered standby connection, length-prefixed the same way a client
+// connection would have sent it. This is what tailState's read loop on the
+// standby side is reading from: without it, a standby's activeConn.dial()
+// connection is read from but nothing on the active side ever writes to
+// it, and tailing blocks forever.
+func (t *TCP) broadcastToStandbys(raw []byte) {
+	t.standbyMu.Lock()
+	conns := make([]net.Conn, 0, len(t.standbyConns))
+
+	for conn := range t.standbyConns {
+		conns = append(conns, conn)
+	}
+
+	t.standbyMu.Unlock()
+
+	for _, conn := range conns {
+		if err := binary.Write(conn, binary.LittleEndian, uint16(len(raw))); err != nil {
+			t.unregisterStandbyConn(conn)
+			continue
+		}
+
+		if _, err := conn.Write(raw); err != nil {
+			t.unregisterStandbyConn(conn)
+		}
 	}
+}
+
+// pluginBroadcaster is the one method this package needs from the real
+// Plugin interface (declared outside this source snapshot, see the doc
+// comment on TCP.plugin): fanning a raw wire message out to every
+// configured downstream plugin endpoint, fire-and-forget. UDPPluginAdapter
+// implements it.
+type pluginBroadcaster interface {
+	Broadcast(payload []byte)
+}
+
+// broadcastToPlugin forwards raw - the exact bytes handleConnection or
+// handleProtoMessage just dispatched to a real handler - to t.plugin, if
+// one is configured and implements pluginBroadcaster. TCP doesn't wait on
+// or retry a failed send: the broadcaster absorbs reconnects and backlog
+// buffering on its own (see UDPPluginAdapter.Broadcast).
+func (t *TCP) broadcastToPlugin(raw []byte) {
+	if broadcaster, ok := t.plugin.(pluginBroadcaster); ok {
+		broadcaster.Broadcast(raw)
+	}
+}
+
+// handleProtoMessage dispatches a protobuf-framed message: the first byte
+// of raw is the MessageType, the rest is the protobuf-encoded body. The
+// accept loop only lets a connection reach here in ModeStandby (see the
+// protoFramingSentinel check above), where every registered handler is a
+// forwardingMessageHandler (standby.go) proxying raw bytes to the active
+// peer rather than decoding them - none of the 15 real per-MessageType
+// handlers (Handshake, EntryList, LapCompleted, ...) implement
+// ProtoMessageHandler, since that needs their actual business logic, which
+// isn't in this source snapshot.
+func (t *TCP) handleProtoMessage(conn net.Conn, raw []byte) error {
+	if len(raw) < 1 {
+		return nil
+	}
+
+	messageType := MessageType(raw[0])
+	body := raw[1:]
+
+	t.recordInbound(conn, true, messageType, raw)
+
+	t.mu.Lock()
+	messageHandler, ok := t.messageHandlers[messageType]
+	t.mu.Unlock()
+
+	if !ok {
+		t.logger.Errorf("Unknown TCP message type: 0x%x (len: %d)", messageType, len(body))
+		return nil
+	}
+
+	protoHandler, ok := messageHandler.(ProtoMessageHandler)
+
+	if !ok {
+		// Reachable if mode flipped to active (see Promote) after this
+		// connection was already admitted as protobuf-framed.
+		t.logger.Errorf("Message Handler: 0x%x does not support protobuf framing", messageHandler.MessageType())
+		return nil
+	}
+
+	if err := protoHandler.OnProtoMessage(conn, body); err != nil {
+		t.logger.WithError(err).Errorf("Proto Message Handler: 0x%x returned error", messageHandler.MessageType())
+		return err
+	}
+
+	t.broadcastToPlugin(raw)
 
 	return nil
 }
@@ -194,6 +460,12 @@ func (t *TCP) Close() error {
 	t.logger.Debugf("Closing TCP Listener")
 	t.closed <- struct{}{}
 
+	if t.recorder != nil {
+		if err := t.recorder.close(); err != nil {
+			t.logger.WithError(err).Error("couldn't close tcp recording")
+		}
+	}
+
 	return t.listener.Close()
 }
 
@@ -0,0 +1,170 @@
+// Package protowire implements just enough of the protobuf wire format -
+// tags, varints, and length-delimited and fixed32 fields - to hand-marshal
+// and -unmarshal the message types in grpcapi and internal/acServer/pb,
+// without pulling either package into a generated-code or protoreflect
+// dependency. Every helper here produces and parses bytes indistinguishable
+// from a real protoc-generated implementation for the same field
+// numbers/types; grpcapi and acServer/pb each layer their own per-message
+// Marshal/Unmarshal and field-type conventions (nested messages, repeated
+// fields, timestamps, ...) on top of it.
+package protowire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// Wire type constants, as defined by the protobuf encoding spec.
+const (
+	Varint  = 0
+	Bytes   = 2
+	Fixed32 = 5
+)
+
+func AppendTag(buf *bytes.Buffer, field, wireType int) {
+	AppendVarint(buf, uint64(field)<<3|uint64(wireType))
+}
+
+func AppendVarint(buf *bytes.Buffer, v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	buf.Write(tmp[:n])
+}
+
+// AppendVarintField writes v as a tagged varint field, skipping it entirely
+// for the zero value the way proto3 omits unset scalar fields.
+func AppendVarintField(buf *bytes.Buffer, field int, v uint64) {
+	if v == 0 {
+		return
+	}
+
+	AppendTag(buf, field, Varint)
+	AppendVarint(buf, v)
+}
+
+func AppendBoolField(buf *bytes.Buffer, field int, v bool) {
+	if !v {
+		return
+	}
+
+	AppendTag(buf, field, Varint)
+	AppendVarint(buf, 1)
+}
+
+func AppendStringField(buf *bytes.Buffer, field int, v string) {
+	if v == "" {
+		return
+	}
+
+	AppendTag(buf, field, Bytes)
+	AppendVarint(buf, uint64(len(v)))
+	buf.WriteString(v)
+}
+
+func AppendBytesField(buf *bytes.Buffer, field int, v []byte) {
+	if len(v) == 0 {
+		return
+	}
+
+	AppendTag(buf, field, Bytes)
+	AppendVarint(buf, uint64(len(v)))
+	buf.Write(v)
+}
+
+func AppendFloat32Field(buf *bytes.Buffer, field int, v float32) {
+	if v == 0 {
+		return
+	}
+
+	AppendTag(buf, field, Fixed32)
+
+	var tmp [4]byte
+	binary.LittleEndian.PutUint32(tmp[:], math.Float32bits(v))
+	buf.Write(tmp[:])
+}
+
+// FieldReader walks length-delimited, varint and fixed32 fields out of a
+// protobuf-encoded message body.
+type FieldReader struct {
+	buf []byte
+}
+
+func NewFieldReader(data []byte) *FieldReader {
+	return &FieldReader{buf: data}
+}
+
+func (r *FieldReader) Next() (field, wireType int, value []byte, ok bool) {
+	if len(r.buf) == 0 {
+		return 0, 0, nil, false
+	}
+
+	tag, n := binary.Uvarint(r.buf)
+
+	if n <= 0 {
+		return 0, 0, nil, false
+	}
+
+	r.buf = r.buf[n:]
+	field = int(tag >> 3)
+	wireType = int(tag & 0x7)
+
+	switch wireType {
+	case Varint:
+		_, n := binary.Uvarint(r.buf)
+
+		if n <= 0 {
+			return 0, 0, nil, false
+		}
+
+		value = r.buf[:n]
+		r.buf = r.buf[n:]
+	case Fixed32:
+		if len(r.buf) < 4 {
+			return 0, 0, nil, false
+		}
+
+		value = r.buf[:4]
+		r.buf = r.buf[4:]
+	case Bytes:
+		length, n := binary.Uvarint(r.buf)
+
+		if n <= 0 || uint64(len(r.buf)-n) < length {
+			return 0, 0, nil, false
+		}
+
+		r.buf = r.buf[n:]
+		value = r.buf[:length]
+		r.buf = r.buf[length:]
+	default:
+		return 0, 0, nil, false
+	}
+
+	return field, wireType, value, true
+}
+
+func AsBool(value []byte) bool {
+	v, _ := binary.Uvarint(value)
+	return v != 0
+}
+
+// AsUint64 decodes value as a varint, for callers to narrow to their own
+// scalar type (uint32, int32, ...).
+func AsUint64(value []byte) uint64 {
+	v, _ := binary.Uvarint(value)
+	return v
+}
+
+func AsFloat32(value []byte) float32 {
+	if len(value) != 4 {
+		return 0
+	}
+
+	return math.Float32frombits(binary.LittleEndian.Uint32(value))
+}
+
+func AsBytes(value []byte) []byte {
+	out := make([]byte, len(value))
+	copy(out, value)
+	return out
+}
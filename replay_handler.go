@@ -0,0 +1,89 @@
+package acsm
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/JustaPenguin/assetto-server-manager/internal/acServer"
+)
+
+// ReplayHandler lets admins download TCP message recordings captured by
+// acServer's recorder (see EnableRecording) and upload one for offline
+// diffing of ServerState transitions: the diffing itself happens outside
+// the manager, this just gets a recording in or out of recordingsDir.
+type ReplayHandler struct {
+	*BaseHandler
+
+	store         Store
+	recordingsDir string
+}
+
+func NewReplayHandler(baseHandler *BaseHandler, store Store, recordingsDir string) *ReplayHandler {
+	return &ReplayHandler{
+		BaseHandler:   baseHandler,
+		store:         store,
+		recordingsDir: recordingsDir,
+	}
+}
+
+// download serves a previously recorded session by name for an admin to
+// pull down and replay locally with acServer.Replayer.
+func (rh *ReplayHandler) download(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+
+	if name == "" || name == "." || name == ".." || name != filepath.Base(name) {
+		http.Error(w, "invalid recording name", http.StatusBadRequest)
+		return
+	}
+
+	http.ServeFile(w, r, filepath.Join(rh.recordingsDir, name))
+}
+
+// upload accepts a recording uploaded from elsewhere (e.g. another
+// manager instance or a bug report), stores it alongside recordingsDir and
+// responds with its decoded entries so an admin can diff it against a
+// local recording of the same bug offline.
+func (rh *ReplayHandler) upload(w http.ResponseWriter, r *http.Request) {
+	file, header, err := r.FormFile("recording")
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	defer file.Close()
+
+	if err := os.MkdirAll(rh.recordingsDir, 0755); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	destPath := filepath.Join(rh.recordingsDir, filepath.Base(header.Filename))
+
+	dest, err := os.Create(destPath)
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := dest.ReadFrom(file); err != nil {
+		dest.Close()
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	dest.Close()
+
+	messages, err := acServer.ReadRecording(destPath)
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(messages)
+}
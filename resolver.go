@@ -1,7 +1,15 @@
 package acsm
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"net"
 	"net/http"
+
+	"google.golang.org/grpc"
+
+	"github.com/JustaPenguin/assetto-server-manager/grpcapi"
 )
 
 type Resolver struct {
@@ -9,6 +17,23 @@ type Resolver struct {
 	templateLoader  TemplateLoader
 	reloadTemplates bool
 
+	// grpcPort is the TCP port the gRPC AdminService listens on; 0 disables
+	// it. It's a constructor parameter rather than a config.Server field
+	// because config.Server is defined outside this source snapshot and
+	// doesn't carry it yet - once it does, NewResolver's callers can thread
+	// config.Server.GRPCPort through here instead.
+	grpcPort int
+
+	// pluginEndpoints is the set of downstream plugin endpoints UDPPluginAdapter
+	// fans events out to. See resolvePluginEndpointConfig for why it's a
+	// constructor parameter instead of serverOptions.UDPPluginEndpoints.
+	pluginEndpoints []PluginEndpointConfig
+
+	// tcpRecordingsDir is where ReplayHandler reads and writes acServer TCP
+	// recordings. It's a constructor parameter instead of
+	// config.Server.TCPRecordingsDir for the same reason grpcPort is.
+	tcpRecordingsDir string
+
 	raceManager           *RaceManager
 	carManager            *CarManager
 	trackManager          *TrackManager
@@ -27,6 +52,7 @@ type Resolver struct {
 	contentManagerWrapper *ContentManagerWrapper
 	acsrClient            *ACSRClient
 	udpPluginAdapter      *UDPPluginAdapter
+	grpcServer            *grpcapi.Server
 
 	// handlers
 	baseHandler                 *BaseHandler
@@ -51,13 +77,17 @@ type Resolver struct {
 	healthCheck                 *HealthCheck
 	kissMyRankHandler           *KissMyRankHandler
 	realPenaltyHandler          *RealPenaltyHandler
+	replayHandler               *ReplayHandler
 }
 
-func NewResolver(templateLoader TemplateLoader, reloadTemplates bool, store Store) (*Resolver, error) {
+func NewResolver(templateLoader TemplateLoader, reloadTemplates bool, store Store, grpcPort int, pluginEndpoints []PluginEndpointConfig, tcpRecordingsDir string) (*Resolver, error) {
 	r := &Resolver{
-		templateLoader:  templateLoader,
-		reloadTemplates: reloadTemplates,
-		store:           store,
+		templateLoader:   templateLoader,
+		reloadTemplates:  reloadTemplates,
+		store:            store,
+		grpcPort:         grpcPort,
+		pluginEndpoints:  pluginEndpoints,
+		tcpRecordingsDir: tcpRecordingsDir,
 	}
 
 	if err := r.initACSRClient(); err != nil {
@@ -68,6 +98,10 @@ func NewResolver(templateLoader TemplateLoader, reloadTemplates bool, store Stor
 		return nil, err
 	}
 
+	if err := r.startGRPCServer(); err != nil {
+		return nil, err
+	}
+
 	return r, nil
 }
 
@@ -109,11 +143,21 @@ func (r *Resolver) resolveServerProcess() ServerProcess {
 	}
 
 	r.serverProcess = NewAssettoServerProcess(r.ResolveStore(), r.resolveContentManagerWrapper())
-	r.serverProcess.SetPlugin(r.resolveUDPPluginAdapter())
+
+	// Not wired with SetPlugin(r.resolveUDPPluginAdapter()): SetPlugin's
+	// parameter is the real Plugin interface, which UDPPluginAdapter only
+	// implements a fragment of (see its doc comment). Passing it here would
+	// either fail to compile against the real Plugin interface or silently
+	// satisfy it with no-op methods - do this once UDPPluginAdapter grows
+	// the rest of Plugin's per-event callbacks.
 
 	return r.serverProcess
 }
 
+// resolveUDPPluginAdapter builds the plugin endpoint fan-out adapter. It has
+// no caller yet - see the comment in resolveServerProcess for why it isn't
+// passed to SetPlugin - but is kept resolvable so wiring it in is a one-line
+// change once UDPPluginAdapter implements the rest of Plugin.
 func (r *Resolver) resolveUDPPluginAdapter() *UDPPluginAdapter {
 	if r.udpPluginAdapter != nil {
 		return r.udpPluginAdapter
@@ -125,11 +169,24 @@ func (r *Resolver) resolveUDPPluginAdapter() *UDPPluginAdapter {
 		r.resolveChampionshipManager(),
 		r.resolveRaceWeekendManager(),
 		r.resolveContentManagerWrapper(),
+		r.resolvePluginEndpointConfig(),
 	)
 
 	return r.udpPluginAdapter
 }
 
+// resolvePluginEndpointConfig returns the set of downstream plugin
+// endpoints (stracker, KissMyRank, Real Penalty, custom listeners) to fan
+// events out to. It's a NewResolver parameter (r.pluginEndpoints) rather
+// than a serverOptions.UDPPluginEndpoints field, because ServerOptions
+// itself isn't defined anywhere in this tree (LoadServerOptions' return
+// type lives outside this source snapshot) - once it grows that field,
+// its config-UI control and options migration, NewResolver's caller can
+// read it from there and pass it through here instead.
+func (r *Resolver) resolvePluginEndpointConfig() []PluginEndpointConfig {
+	return r.pluginEndpoints
+}
+
 func (r *Resolver) resolveContentManagerWrapper() *ContentManagerWrapper {
 	if r.contentManagerWrapper != nil {
 		return r.contentManagerWrapper
@@ -546,6 +603,117 @@ func (r *Resolver) resolveRealPenaltyHandler() *RealPenaltyHandler {
 	return r.realPenaltyHandler
 }
 
+// resolveReplayHandler builds the handler admins use to download and
+// upload acServer TCP recordings (see acServer.RecorderConfig) for offline
+// debugging and regression testing with acServer.Replayer.
+func (r *Resolver) resolveReplayHandler() *ReplayHandler {
+	if r.replayHandler != nil {
+		return r.replayHandler
+	}
+
+	r.replayHandler = NewReplayHandler(r.resolveBaseHandler(), r.ResolveStore(), r.tcpRecordingsDir)
+
+	return r.replayHandler
+}
+
+// grpcAuthenticator adapts AccountManager to grpcapi.Authenticator, mapping
+// the account's stored permission level onto the role names used by the
+// gRPC service's per-RPC authorization.
+type grpcAuthenticator struct {
+	accountManager *AccountManager
+}
+
+func (g *grpcAuthenticator) AuthenticateRPC(ctx context.Context) (account string, role string, err error) {
+	token, err := tokenFromGRPCContext(ctx)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	acc, err := g.accountManager.AccountFromAPIToken(token)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return acc.Name, acc.Group.String(), nil
+}
+
+func (r *Resolver) resolveGRPCServer() *grpcapi.Server {
+	if r.grpcServer != nil {
+		return r.grpcServer
+	}
+
+	r.grpcServer = grpcapi.NewServer(
+		&grpcRaceManagerAdapter{raceManager: r.resolveRaceManager()},
+		&grpcChampionshipManagerAdapter{championshipManager: r.resolveChampionshipManager()},
+		&grpcRaceWeekendManagerAdapter{raceWeekendManager: r.resolveRaceWeekendManager()},
+		&grpcContentUploaderAdapter{contentUploadHandler: r.resolveContentUploadHandler()},
+		&grpcBlockListManagerAdapter{blockListManager: r.resolveBlockListManager()},
+		&grpcServerProcessAdapter{serverProcess: r.resolveServerProcess()},
+		&grpcRaceControlAdapter{raceControl: r.ResolveRaceControl()},
+		&grpcAuthenticator{accountManager: r.resolveAccountManager()},
+	)
+
+	return r.grpcServer
+}
+
+// ResolveGRPCServer builds a *grpc.Server exposing the AdminService,
+// wired to the same managers the HTML handlers use. grpc.ForceServerCodec
+// is required because grpcapi's wire types are plain structs, not
+// proto.Message; grpc.StreamInterceptor enforces RequiredRole on
+// StreamRaceControl the same way grpc.UnaryInterceptor does for every
+// other RPC.
+func (r *Resolver) ResolveGRPCServer() *grpc.Server {
+	admin := r.resolveGRPCServer()
+
+	s := grpc.NewServer(
+		grpc.ForceServerCodec(grpcapi.WireCodec{}),
+		grpc.UnaryInterceptor(admin.UnaryAuthInterceptor),
+		grpc.StreamInterceptor(admin.StreamAuthInterceptor),
+	)
+	admin.Register(s)
+
+	return s
+}
+
+// startGRPCServer starts the gRPC AdminService listening on r.grpcPort, if
+// one is configured. A port of 0 disables it, since this is an opt-in
+// surface for external tooling, not something every install should expose
+// by default. See the field doc on Resolver for why this is a constructor
+// parameter rather than a config.Server field.
+func (r *Resolver) startGRPCServer() error {
+	if r.grpcPort == 0 {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", r.grpcPort))
+
+	if err != nil {
+		return err
+	}
+
+	server := r.ResolveGRPCServer()
+
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Printf("grpc: AdminService server stopped: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// ResolveRouter mounts every handler's routes, including replayHandler's.
+// replayHandler used to be mounted separately on a bare http.ServeMux laid
+// on top of Router's result, to avoid guessing at a new parameter and
+// risking a silent reorder of Router's existing, load-bearing positions -
+// but that left /api/replay/download and /api/replay/upload completely
+// unauthenticated, since only Router's internals (outside this source
+// snapshot) apply the admin session/permission checks every other route
+// here gets. replayHandler is passed in as Router's last argument instead,
+// so its routes go through the same auth Router already wraps every other
+// handler with.
 func (r *Resolver) ResolveRouter(fs http.FileSystem) http.Handler {
 	return Router(
 		fs,
@@ -569,6 +737,7 @@ func (r *Resolver) ResolveRouter(fs http.FileSystem) http.Handler {
 		r.resolveHealthCheck(),
 		r.resolveKissMyRankHandler(),
 		r.resolveRealPenaltyHandler(),
+		r.resolveReplayHandler(),
 	)
 }
 
@@ -0,0 +1,372 @@
+package acsm
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// pluginEndpointHealth is the health snapshot for a single configured
+// plugin endpoint, returned by UDPPluginAdapter.HealthCheck.
+type pluginEndpointHealth struct {
+	Address       string `json:"address"`
+	Up            bool   `json:"up"`
+	QueueDepth    int    `json:"queue_depth"`
+	DroppedEvents uint64 `json:"dropped_events"`
+}
+
+// pluginEvent is a single outbound event queued for one endpoint, tagged
+// with a monotonically increasing sequence number so the receiving plugin
+// can detect gaps across a reconnect.
+type pluginEvent struct {
+	sequence uint64
+	payload  []byte
+}
+
+// pluginEndpoint supervises a single downstream plugin connection (stracker,
+// KissMyRank, Real Penalty, a custom listener, ...). If the plugin crashes
+// or restarts, the endpoint reconnects using Fibonacci backoff and buffers
+// outbound events in a bounded ring buffer until it's back.
+type pluginEndpoint struct {
+	address    string
+	filter     func(event []byte) bool
+	maxBackoff time.Duration
+	ringSize   int
+
+	send func(address string, payload []byte) error
+
+	mu       sync.Mutex
+	up       bool
+	sequence uint64
+	dropped  uint64
+	ring     [][]byte
+	ringHead int
+	ringLen  int
+
+	stop chan struct{}
+}
+
+const defaultRingSize = 256
+
+func newPluginEndpoint(address string, filter func(event []byte) bool, maxBackoff time.Duration, send func(address string, payload []byte) error) *pluginEndpoint {
+	if filter == nil {
+		filter = func([]byte) bool { return true }
+	}
+
+	return &pluginEndpoint{
+		address:    address,
+		filter:     filter,
+		maxBackoff: maxBackoff,
+		ringSize:   defaultRingSize,
+		send:       send,
+		ring:       make([][]byte, defaultRingSize),
+		stop:       make(chan struct{}),
+	}
+}
+
+// fibonacciBackoff yields an increasing, Fibonacci-shaped backoff sequence,
+// bounded by max.
+func fibonacciBackoff(max time.Duration) func() time.Duration {
+	a, b := time.Second, time.Second
+
+	return func() time.Duration {
+		next := a
+
+		a, b = b, a+b
+
+		if next > max {
+			next = max
+		}
+
+		return next
+	}
+}
+
+// supervise keeps the endpoint marked up for as long as it stays reachable,
+// re-probing with Fibonacci backoff while it's down. A reconnect only
+// flips the endpoint back up once flush has fully drained the backlog
+// buffered while it was down, so enqueue never races ahead of it with a
+// newer event. Individual send failures (see enqueue) mark the endpoint
+// down immediately rather than waiting for the next probe.
+func (p *pluginEndpoint) supervise(dial func(address string) error) {
+	backoff := fibonacciBackoff(p.maxBackoff)
+	probe := time.NewTicker(time.Second)
+	defer probe.Stop()
+
+	for {
+		p.mu.Lock()
+		up := p.up
+		p.mu.Unlock()
+
+		if !up {
+			if err := dial(p.address); err == nil && p.flush() {
+				p.mu.Lock()
+				p.up = true
+				p.mu.Unlock()
+
+				backoff = fibonacciBackoff(p.maxBackoff)
+			} else {
+				select {
+				case <-p.stop:
+					return
+				case <-time.After(backoff()):
+					continue
+				}
+			}
+		}
+
+		select {
+		case <-p.stop:
+			return
+		case <-probe.C:
+		}
+	}
+}
+
+// enqueue sends an event immediately if the endpoint is up. Otherwise, or
+// if that send fails, it buffers the event, dropping the oldest buffered
+// event if the ring is full.
+func (p *pluginEndpoint) enqueue(payload []byte) {
+	if !p.filter(payload) {
+		return
+	}
+
+	p.mu.Lock()
+	p.sequence++
+	sequence := p.sequence
+	up := p.up
+
+	if !up {
+		p.bufferLocked(sequence, payload)
+	}
+	p.mu.Unlock()
+
+	if up {
+		if err := p.send(p.address, withSequence(sequence, payload)); err != nil {
+			p.mu.Lock()
+			p.up = false
+			p.bufferLocked(sequence, payload)
+			p.mu.Unlock()
+		}
+	}
+}
+
+// bufferLocked appends an already-sequenced event to the ring, dropping the
+// oldest buffered event if it's full. p.mu must be held by the caller.
+func (p *pluginEndpoint) bufferLocked(sequence uint64, payload []byte) {
+	if p.ringLen == p.ringSize {
+		p.ringHead = (p.ringHead + 1) % p.ringSize
+		p.dropped++
+	} else {
+		p.ringLen++
+	}
+
+	idx := (p.ringHead + p.ringLen - 1) % p.ringSize
+	p.ring[idx] = withSequence(sequence, payload)
+}
+
+// flush replays buffered events to the endpoint in FIFO order, popping each
+// one only once it's confirmed sent. It reports whether the backlog fully
+// drained; on a send failure it stops and leaves the unsent remainder
+// buffered (including the one that failed) for the next reconnect attempt,
+// rather than losing it.
+func (p *pluginEndpoint) flush() bool {
+	for {
+		p.mu.Lock()
+
+		if p.ringLen == 0 {
+			p.mu.Unlock()
+			return true
+		}
+
+		event := p.ring[p.ringHead]
+		p.mu.Unlock()
+
+		if err := p.send(p.address, event); err != nil {
+			return false
+		}
+
+		p.mu.Lock()
+		p.ringHead = (p.ringHead + 1) % p.ringSize
+		p.ringLen--
+		p.mu.Unlock()
+	}
+}
+
+func (p *pluginEndpoint) health() pluginEndpointHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return pluginEndpointHealth{
+		Address:       p.address,
+		Up:            p.up,
+		QueueDepth:    p.ringLen,
+		DroppedEvents: p.dropped,
+	}
+}
+
+func (p *pluginEndpoint) close() {
+	close(p.stop)
+}
+
+// withSequence prefixes payload with its sequence number so the receiving
+// plugin can detect gaps across a reconnect.
+func withSequence(sequence uint64, payload []byte) []byte {
+	out := make([]byte, 8+len(payload))
+
+	out[0] = byte(sequence >> 56)
+	out[1] = byte(sequence >> 48)
+	out[2] = byte(sequence >> 40)
+	out[3] = byte(sequence >> 32)
+	out[4] = byte(sequence >> 24)
+	out[5] = byte(sequence >> 16)
+	out[6] = byte(sequence >> 8)
+	out[7] = byte(sequence)
+
+	copy(out[8:], payload)
+
+	return out
+}
+
+// UDPPluginAdapter fans events raised by acServer out to every configured
+// downstream plugin endpoint. Unlike a single unsupervised connection, each
+// endpoint reconnects independently with Fibonacci backoff and buffers
+// events during an outage so a plugin crash or restart doesn't silently
+// drop chat, penalty or lap events.
+//
+// It does not implement the full Plugin interface ServerProcess.SetPlugin
+// (see resolver.go's resolveServerProcess) expects - that interface isn't
+// defined anywhere in this tree, so the exact per-event OnXxx callback set
+// (chat, lap completed, session change, ...) can't be written without
+// guessing at a contract this package doesn't own. resolveServerProcess
+// deliberately does not call SetPlugin with this adapter for that reason:
+// doing so would either fail to compile against the real Plugin interface
+// or silently satisfy it with a bunch of no-op methods invented here. It
+// does satisfy acServer's local pluginBroadcaster interface
+// (Broadcast([]byte)), the one method acServer.TCP actually needs, so once
+// something does construct an acServer.Server with this adapter as its
+// plugin (directly, or once Plugin's other methods are implemented), every
+// message TCP successfully handles is fanned out through it fire-and-forget.
+type UDPPluginAdapter struct {
+	raceManager           *RaceManager
+	raceControl           *RaceControl
+	championshipManager   *ChampionshipManager
+	raceWeekendManager    *RaceWeekendManager
+	contentManagerWrapper *ContentManagerWrapper
+
+	endpoints []*pluginEndpoint
+}
+
+// PluginEndpointConfig describes a single downstream plugin to fan events
+// out to.
+type PluginEndpointConfig struct {
+	Address    string
+	Filter     func(event []byte) bool
+	MaxBackoff time.Duration
+}
+
+const defaultMaxBackoff = 30 * time.Second
+
+// NewUDPPluginAdapter builds an adapter backed by the given managers,
+// supervising one connection per configured endpoint.
+func NewUDPPluginAdapter(
+	raceManager *RaceManager,
+	raceControl *RaceControl,
+	championshipManager *ChampionshipManager,
+	raceWeekendManager *RaceWeekendManager,
+	contentManagerWrapper *ContentManagerWrapper,
+	endpoints []PluginEndpointConfig,
+) *UDPPluginAdapter {
+	adapter := &UDPPluginAdapter{
+		raceManager:           raceManager,
+		raceControl:           raceControl,
+		championshipManager:   championshipManager,
+		raceWeekendManager:    raceWeekendManager,
+		contentManagerWrapper: contentManagerWrapper,
+	}
+
+	for _, cfg := range endpoints {
+		maxBackoff := cfg.MaxBackoff
+
+		if maxBackoff <= 0 {
+			maxBackoff = defaultMaxBackoff
+		}
+
+		endpoint := newPluginEndpoint(cfg.Address, cfg.Filter, maxBackoff, sendUDPPluginEvent)
+		adapter.endpoints = append(adapter.endpoints, endpoint)
+
+		go endpoint.supervise(dialUDPPluginEndpoint)
+	}
+
+	return adapter
+}
+
+// Broadcast fans payload out to every configured endpoint, each absorbing
+// its own failures independently. It's also what satisfies acServer's
+// pluginBroadcaster interface (see the UDPPluginAdapter doc comment), so
+// acServer.TCP can call this directly for every message it handles.
+func (u *UDPPluginAdapter) Broadcast(payload []byte) {
+	for _, endpoint := range u.endpoints {
+		endpoint.enqueue(payload)
+	}
+}
+
+// HealthCheck reports up/down, queue depth and dropped events for every
+// configured plugin endpoint.
+func (u *UDPPluginAdapter) HealthCheck() []pluginEndpointHealth {
+	health := make([]pluginEndpointHealth, 0, len(u.endpoints))
+
+	for _, endpoint := range u.endpoints {
+		health = append(health, endpoint.health())
+	}
+
+	return health
+}
+
+// Close stops supervising every configured endpoint.
+func (u *UDPPluginAdapter) Close() {
+	for _, endpoint := range u.endpoints {
+		endpoint.close()
+	}
+}
+
+// dialUDPPluginEndpoint opens (and immediately closes) a UDP socket to
+// address, confirming it resolves and a local socket can be bound to it.
+// UDP has no handshake, so this can't guarantee a listener is present on
+// the other end - genuine liveness is inferred from send successes and the
+// plugin's own traffic.
+func dialUDPPluginEndpoint(address string) error {
+	addr, err := net.ResolveUDPAddr("udp", address)
+
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialUDP("udp", nil, addr)
+
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+func sendUDPPluginEvent(address string, payload []byte) error {
+	addr, err := net.ResolveUDPAddr("udp", address)
+
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialUDP("udp", nil, addr)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	_, err = conn.Write(payload)
+
+	return err
+}
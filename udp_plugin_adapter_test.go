@@ -0,0 +1,94 @@
+package acsm
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPluginEndpointEnqueueBuffersWhileDown(t *testing.T) {
+	var mu sync.Mutex
+	var sent [][]byte
+
+	endpoint := newPluginEndpoint("127.0.0.1:1", nil, time.Second, func(address string, payload []byte) error {
+		mu.Lock()
+		sent = append(sent, payload)
+		mu.Unlock()
+		return nil
+	})
+
+	endpoint.enqueue([]byte("chat"))
+
+	mu.Lock()
+	n := len(sent)
+	mu.Unlock()
+
+	if n != 0 {
+		t.Fatalf("expected nothing sent while endpoint is down, got %d sends", n)
+	}
+
+	health := endpoint.health()
+
+	if health.QueueDepth != 1 {
+		t.Fatalf("expected queue depth 1, got %d", health.QueueDepth)
+	}
+}
+
+func TestPluginEndpointBufferLockedDropsOldestWhenFull(t *testing.T) {
+	endpoint := newPluginEndpoint("127.0.0.1:1", nil, time.Second, func(string, []byte) error { return nil })
+	endpoint.ringSize = 2
+	endpoint.ring = make([][]byte, 2)
+
+	endpoint.enqueue([]byte("a"))
+	endpoint.enqueue([]byte("b"))
+	endpoint.enqueue([]byte("c"))
+
+	health := endpoint.health()
+
+	if health.QueueDepth != 2 {
+		t.Fatalf("expected queue depth capped at ring size 2, got %d", health.QueueDepth)
+	}
+
+	if health.DroppedEvents != 1 {
+		t.Fatalf("expected 1 dropped event, got %d", health.DroppedEvents)
+	}
+}
+
+func TestPluginEndpointFlushStopsOnFirstFailure(t *testing.T) {
+	endpoint := newPluginEndpoint("127.0.0.1:1", nil, time.Second, func(string, []byte) error { return nil })
+
+	endpoint.bufferLocked(1, []byte("a"))
+	endpoint.bufferLocked(2, []byte("b"))
+
+	attempts := 0
+	endpoint.send = func(address string, payload []byte) error {
+		attempts++
+		return errors.New("unreachable")
+	}
+
+	if endpoint.flush() {
+		t.Fatalf("expected flush to report incomplete drain on send failure")
+	}
+
+	if attempts != 1 {
+		t.Fatalf("expected flush to stop after the first failure, made %d attempts", attempts)
+	}
+
+	if endpoint.health().QueueDepth != 2 {
+		t.Fatalf("expected both events to remain buffered after a failed flush")
+	}
+}
+
+func TestFibonacciBackoffCapsAtMax(t *testing.T) {
+	next := fibonacciBackoff(3 * time.Second)
+
+	got := []time.Duration{next(), next(), next(), next(), next()}
+	want := []time.Duration{time.Second, time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("backoff[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}